@@ -17,27 +17,86 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // CanarySpec defines the desired state of Canary
 type CanarySpec struct {
-	Env        map[string]VarSource `yaml:"env,omitempty" json:"env,omitempty"`
-	HTTP       []HTTPCheck          `yaml:"http,omitempty" json:"http,omitempty"`
-	DNS        []DNSCheck           `yaml:"dns,omitempty" json:"dns,omitempty"`
-	DockerPull []DockerPullCheck    `yaml:"docker,omitempty" json:"docker,omitempty"`
-	DockerPush []DockerPushCheck    `yaml:"dockerPush,omitempty" json:"dockerPush,omitempty"`
-	S3         []S3Check            `yaml:"s3,omitempty" json:"s3,omitempty"`
-	S3Bucket   []S3BucketCheck      `yaml:"s3Bucket,omitempty" json:"s3Bucket,omitempty"`
-	TCP        []TCPCheck           `yaml:"tcp,omitempty" json:"tcp,omitempty"`
-	Pod        []PodCheck           `yaml:"pod,omitempty" json:"pod,omitempty"`
-	LDAP       []LDAPCheck          `yaml:"ldap,omitempty" json:"ldap,omitempty"`
-	SSL        []SSLCheck           `yaml:"ssl,omitempty" json:"ssl,omitempty"`
-	ICMP       []ICMPCheck          `yaml:"icmp,omitempty" json:"icmp,omitempty"`
-	Postgres   []PostgresCheck      `yaml:"postgres,omitempty" json:"postgres,omitempty"`
-	Helm       []HelmCheck          `yaml:"helm,omitempty" json:"helm,omitempty"`
-	Namespace  []NamespaceCheck     `yaml:"namespace,omitempty" json:"namespace,omitempty"`
-	Interval   int64                `json:"interval,omitempty"`
+	Env            map[string]VarSource  `yaml:"env,omitempty" json:"env,omitempty"`
+	HTTP           []HTTPCheck           `yaml:"http,omitempty" json:"http,omitempty"`
+	DNS            []DNSCheck            `yaml:"dns,omitempty" json:"dns,omitempty"`
+	DockerPull     []DockerPullCheck     `yaml:"docker,omitempty" json:"docker,omitempty"`
+	DockerPush     []DockerPushCheck     `yaml:"dockerPush,omitempty" json:"dockerPush,omitempty"`
+	S3             []S3Check             `yaml:"s3,omitempty" json:"s3,omitempty"`
+	S3Bucket       []S3BucketCheck       `yaml:"s3Bucket,omitempty" json:"s3Bucket,omitempty"`
+	TCP            []TCPCheck            `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+	Pod            []PodCheck            `yaml:"pod,omitempty" json:"pod,omitempty"`
+	LDAP           []LDAPCheck           `yaml:"ldap,omitempty" json:"ldap,omitempty"`
+	SSL            []SSLCheck            `yaml:"ssl,omitempty" json:"ssl,omitempty"`
+	ICMP           []ICMPCheck           `yaml:"icmp,omitempty" json:"icmp,omitempty"`
+	Postgres       []PostgresCheck       `yaml:"postgres,omitempty" json:"postgres,omitempty"`
+	Helm           []HelmCheck           `yaml:"helm,omitempty" json:"helm,omitempty"`
+	Namespace      []NamespaceCheck      `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	SSH            []SSHCheck            `yaml:"ssh,omitempty" json:"ssh,omitempty"`
+	Prometheus     []PrometheusCheck     `yaml:"prometheus,omitempty" json:"prometheus,omitempty"`
+	Kubernetes     []KubernetesCheck     `yaml:"kubernetes,omitempty" json:"kubernetes,omitempty"`
+	Job            []JobCheck            `yaml:"job,omitempty" json:"job,omitempty"`
+	Connectivity   []ConnectivityCheck   `yaml:"connectivity,omitempty" json:"connectivity,omitempty"`
+	Volume         []VolumeCheck         `yaml:"volume,omitempty" json:"volume,omitempty"`
+	Swift          []SwiftCheck          `yaml:"swift,omitempty" json:"swift,omitempty"`
+	SwiftContainer []SwiftContainerCheck `yaml:"swiftContainer,omitempty" json:"swiftContainer,omitempty"`
+	AzureBlob      []AzureBlobCheck      `yaml:"azureBlob,omitempty" json:"azureBlob,omitempty"`
+	AzureContainer []AzureContainerCheck `yaml:"azureContainer,omitempty" json:"azureContainer,omitempty"`
+	Filesystem     []FilesystemCheck     `yaml:"filesystem,omitempty" json:"filesystem,omitempty"`
+	Certificate    []CertificateCheck    `yaml:"certificate,omitempty" json:"certificate,omitempty"`
+	OIDC           []OIDCCheck           `yaml:"oidc,omitempty" json:"oidc,omitempty"`
+	// Interval in seconds to run the checks on, unless a schedule is specified
+	Interval int64 `json:"interval,omitempty"`
+	// Schedule is a cron expression or descriptor such as @hourly or "@every 10m" to run the
+	// checks on, overriding the interval
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	// Schedules overrides the schedule of every check of the given type, e.g. helm: "@hourly"
+	Schedules map[string]string `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+	// Retries is the number of times a failed check is retried with an exponential backoff before
+	// it counts as failed
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty"`
+	// FailureThreshold is the number of consecutive failures before a check is reported as failed
+	FailureThreshold int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	// SuccessThreshold is the number of consecutive successes before a failed check is reported as passing
+	SuccessThreshold int `yaml:"successThreshold,omitempty" json:"successThreshold,omitempty"`
+	// Maintenance windows during which the checks of the canary are recorded as maintenance
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+}
+
+// MaintenanceWindow is either a recurring window starting on a cron schedule and lasting for a
+// duration, or an absolute time range
+type MaintenanceWindow struct {
+	// Schedule is a cron expression at which the window starts, e.g. "0 2 * * SUN"
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	// Duration of the recurring window, e.g. "2h"
+	Duration string `yaml:"duration,omitempty" json:"duration,omitempty"`
+	// Start of the time range in RFC3339 format, the range is open if empty
+	Start string `yaml:"start,omitempty" json:"start,omitempty"`
+	// End of the time range in RFC3339 format, the range is open if empty
+	End    string `yaml:"end,omitempty" json:"end,omitempty"`
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// GetSchedule returns the cron schedule to run the checks of checkType on, or an empty string if
+// they should not be scheduled
+func (spec CanarySpec) GetSchedule(checkType string) string {
+	if schedule, ok := spec.Schedules[checkType]; ok {
+		return schedule
+	}
+	if spec.Schedule != "" {
+		return spec.Schedule
+	}
+	if spec.Interval > 0 {
+		return fmt.Sprintf("@every %ds", spec.Interval)
+	}
+	return ""
 }
 
 type CanaryStatusCondition string
@@ -46,6 +105,10 @@ var (
 	Passed  CanaryStatusCondition = "Passed"
 	Failed  CanaryStatusCondition = "Failed"
 	Invalid CanaryStatusCondition = "Invalid"
+	// Blocked canaries have not failed themselves, but depend on a check that is failing
+	Blocked CanaryStatusCondition = "Blocked"
+	// Maintenance canaries only have checks in a maintenance window or silence
+	Maintenance CanaryStatusCondition = "Maintenance"
 )
 
 // CanaryStatus defines the observed state of Canary
@@ -58,6 +121,9 @@ type CanaryStatus struct {
 	Status *CanaryStatusCondition `json:"status,omitempty"`
 	// +optional
 	Message *string `json:"message,omitempty"`
+	// BlockedBy is the check that is the root cause of a blocked canary
+	// +optional
+	BlockedBy string `json:"blockedBy,omitempty"`
 	// If set, this represents the .metadata.generation that the status was set for
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,3,opt,name=observedGeneration"`
@@ -73,6 +139,7 @@ type CanaryStatus struct {
 
 // Canary is the Schema for the canaries API
 // +kubebuilder:printcolumn:name="Interval",type=string,JSONPath=`.spec.interval`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
 // +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`
 // +kubebuilder:printcolumn:name="Uptime 1H",type=string,JSONPath=`.status.uptime1h`
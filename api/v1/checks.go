@@ -2,11 +2,18 @@ package v1
 
 import (
 	"fmt"
+	"net"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 type HTTPCheck struct {
-	Description string `yaml:"description" json:"description,omitempty"`
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
 	// HTTP endpoint to crawl
 	Endpoint string `yaml:"endpoint" json:"endpoint,omitempty"`
 	// Maximum duration in milliseconds for the HTTP request. It will fail the check if it takes longer.
@@ -16,7 +23,8 @@ type HTTPCheck struct {
 	// Exact response content expected to be returned by the endpoint.
 	ResponseContent string `yaml:"responseContent" json:"responseContent,omitempty"`
 	// Maximum number of days until the SSL Certificate expires.
-	MaxSSLExpiry int `yaml:"maxSSLExpiry" json:"maxSSLExpiry,omitempty"`
+	MaxSSLExpiry int   `yaml:"maxSSLExpiry" json:"maxSSLExpiry,omitempty"`
+	Timeout      int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 type SSLCheck struct {
@@ -35,16 +43,41 @@ func (c HTTPCheck) GetDescription() string {
 	return c.Description
 }
 
+func (c HTTPCheck) GetName() string {
+	return c.Name
+}
+
+func (c HTTPCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c HTTPCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c HTTPCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
 func (c HTTPCheck) GetType() string {
 	return "http"
 }
 
+func (c HTTPCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
 type ICMPCheck struct {
-	Description         string `yaml:"description" json:"description,omitempty"`
-	Endpoint            string `yaml:"endpoint" json:"endpoint,omitempty"`
-	ThresholdMillis     int64  `yaml:"thresholdMillis" json:"thresholdMillis,omitempty"`
-	PacketLossThreshold int64  `yaml:"packetLossThreshold" json:"packetLossThreshold,omitempty"`
-	PacketCount         int    `yaml:"packetCount" json:"packetCount,omitempty"`
+	Description         string              `yaml:"description" json:"description,omitempty"`
+	Name                string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels              map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn           []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance         []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Endpoint            string              `yaml:"endpoint" json:"endpoint,omitempty"`
+	ThresholdMillis     int64               `yaml:"thresholdMillis" json:"thresholdMillis,omitempty"`
+	PacketLossThreshold int64               `yaml:"packetLossThreshold" json:"packetLossThreshold,omitempty"`
+	PacketCount         int                 `yaml:"packetCount" json:"packetCount,omitempty"`
+	Timeout             int64               `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 type TCPCheck struct {
@@ -61,294 +94,1431 @@ func (c ICMPCheck) GetDescription() string {
 	return c.Description
 }
 
+func (c ICMPCheck) GetName() string {
+	return c.Name
+}
+
+func (c ICMPCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c ICMPCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c ICMPCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
 func (c ICMPCheck) GetType() string {
 	return "icmp"
 }
 
+func (c ICMPCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
 type Bucket struct {
 	Name     string `yaml:"name" json:"name,omitempty"`
 	Region   string `yaml:"region" json:"region,omitempty"`
 	Endpoint string `yaml:"endpoint" json:"endpoint,omitempty"`
 }
 
-type S3Check struct {
-	Description string `yaml:"description" json:"description,omitempty"`
-	Bucket      Bucket `yaml:"bucket" json:"bucket,omitempty"`
-	AccessKey   string `yaml:"accessKey" json:"accessKey,omitempty"`
-	SecretKey   string `yaml:"secretKey" json:"secretKey,omitempty"`
-	ObjectPath  string `yaml:"objectPath" json:"objectPath,omitempty"`
-	// Skip TLS verify when connecting to s3
-	SkipTLSVerify bool `yaml:"skipTLSVerify" json:"skipTLSVerify,omitempty"`
+type S3Check struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Bucket      Bucket              `yaml:"bucket" json:"bucket,omitempty"`
+	AccessKey   string              `yaml:"accessKey" json:"accessKey,omitempty"`
+	SecretKey   string              `yaml:"secretKey" json:"secretKey,omitempty"`
+	ObjectPath  string              `yaml:"objectPath" json:"objectPath,omitempty"`
+	// Skip TLS verify when connecting to s3
+	SkipTLSVerify bool `yaml:"skipTLSVerify" json:"skipTLSVerify,omitempty"`
+	// Size of the random object to upload as a quantity, e.g. 10Mi, defaults to 16 bytes
+	ObjectSize string `yaml:"objectSize,omitempty" json:"objectSize,omitempty"`
+	// Objects larger than the part size are uploaded in multiple parts, defaults to 5Mi
+	PartSize string `yaml:"partSize,omitempty" json:"partSize,omitempty"`
+	// Checksum is either md5 (default) to verify the ETag of the object, which is skipped for objects
+	// encrypted with KMS or customer keys, or sha256 to verify a checksum stored in the object metadata
+	Checksum string `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+	// Fail if versioning is not enabled on the bucket
+	Versioning bool `yaml:"versioning,omitempty" json:"versioning,omitempty"`
+	// Fail if object lock is not enabled on the bucket
+	ObjectLock bool  `yaml:"objectLock,omitempty" json:"objectLock,omitempty"`
+	Timeout    int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c S3Check) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", c.Bucket.Endpoint, c.Bucket.Name)
+}
+
+func (c S3Check) GetDescription() string {
+	return c.Description
+}
+
+func (c S3Check) GetName() string {
+	return c.Name
+}
+
+func (c S3Check) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c S3Check) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c S3Check) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c S3Check) GetType() string {
+	return "s3"
+}
+
+func (c S3Check) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type S3BucketCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Bucket      string              `yaml:"bucket" json:"bucket,omitempty"`
+	AccessKey   string              `yaml:"accessKey" json:"accessKey,omitempty"`
+	SecretKey   string              `yaml:"secretKey" json:"secretKey,omitempty"`
+	Region      string              `yaml:"region" json:"region,omitempty"`
+	Endpoint    string              `yaml:"endpoint" json:"endpoint,omitempty"`
+	// glob path to restrict matches to a subset
+	ObjectPath string `yaml:"objectPath" json:"objectPath,omitempty"`
+	ReadWrite  bool   `yaml:"readWrite" json:"readWrite,omitempty"`
+	// maximum allowed age of matched objects in seconds
+	MaxAge int64 `yaml:"maxAge" json:"maxAge,omitempty"`
+	// min size of of most recent matched object in bytes
+	MinSize int64 `yaml:"minSize" json:"minSize,omitempty"`
+	// Name or number of a capture group of objectPath to group objects by, maxAge, minSize
+	// and maxSizeDrop are then evaluated for the most recent object of every group
+	GroupBy string `yaml:"groupBy,omitempty" json:"groupBy,omitempty"`
+	// Maximum percentage the most recent object may be smaller than the previous object of the same group
+	MaxSizeDrop int64 `yaml:"maxSizeDrop,omitempty" json:"maxSizeDrop,omitempty"`
+	// Use path style path: http://s3.amazonaws.com/BUCKET/KEY instead of http://BUCKET.s3.amazonaws.com/KEY
+	UsePathStyle bool `yaml:"usePathStyle" json:"usePathStyle,omitempty"`
+	// Skip TLS verify when connecting to s3
+	SkipTLSVerify bool  `yaml:"skipTLSVerify" json:"skipTLSVerify,omitempty"`
+	Timeout       int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (s3 S3BucketCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", s3.Endpoint, s3.Bucket)
+}
+
+func (c S3BucketCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c S3BucketCheck) GetName() string {
+	return c.Name
+}
+
+func (c S3BucketCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c S3BucketCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c S3BucketCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c S3BucketCheck) GetType() string {
+	return "s3Bucket"
+}
+
+func (c S3BucketCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type DockerPullCheck struct {
+	Description    string              `yaml:"description" json:"description,omitempty"`
+	Name           string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels         map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn      []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance    []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Image          string              `yaml:"image" json:"image,omitempty"`
+	Username       string              `yaml:"username" json:"username,omitempty"`
+	Password       string              `yaml:"password" json:"password,omitempty"`
+	ExpectedDigest string              `yaml:"expectedDigest" json:"expectedDigest,omitempty"`
+	// Expected size of the image, uncompressed when pulled through the daemon
+	// and the compressed size of the layers when pulled from the registry
+	ExpectedSize int64 `yaml:"expectedSize" json:"expectedSize,omitempty"`
+	// Mode is either daemon (default) to pull the image using the docker daemon,
+	// or registry to fetch it from the registry API directly
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// Download every layer and verify its digest, only used in registry mode
+	VerifyBlobs   bool `yaml:"verifyBlobs,omitempty" json:"verifyBlobs,omitempty"`
+	SkipTLSVerify bool `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	// Connect to the registry over HTTP instead of HTTPS, only used in registry mode
+	PlainHTTP bool  `yaml:"plainHTTP,omitempty" json:"plainHTTP,omitempty"`
+	Timeout   int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c DockerPullCheck) GetEndpoint() string {
+	return c.Image
+}
+
+func (c DockerPullCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c DockerPullCheck) GetName() string {
+	return c.Name
+}
+
+func (c DockerPullCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c DockerPullCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c DockerPullCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c DockerPullCheck) GetType() string {
+	return "dockerPull"
+}
+
+func (c DockerPullCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type DockerPushCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Image       string              `yaml:"image" json:"image,omitempty"`
+	Username    string              `yaml:"username" json:"username,omitempty"`
+	Password    string              `yaml:"password" json:"password,omitempty"`
+	// Mode is either daemon (default) to push an existing image using the docker daemon, or registry
+	// to push a generated image under a timestamped tag of the image repository, pull it back and delete it
+	Mode          string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	SkipTLSVerify bool   `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	// Connect to the registry over HTTP instead of HTTPS, only used in registry mode
+	PlainHTTP bool  `yaml:"plainHTTP,omitempty" json:"plainHTTP,omitempty"`
+	Timeout   int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c DockerPushCheck) GetEndpoint() string {
+	return c.Image
+}
+
+func (c DockerPushCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c DockerPushCheck) GetName() string {
+	return c.Name
+}
+
+func (c DockerPushCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c DockerPushCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c DockerPushCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c DockerPushCheck) GetType() string {
+	return "dockerPush"
+}
+
+func (c DockerPushCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type PostgresCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Driver      string              `yaml:"driver" json:"driver,omitempty"`
+	Connection  string              `yaml:"connection" json:"connection,omitempty"`
+	Query       string              `yaml:"query" json:"query,omitempty"`
+	Result      int                 `yaml:"results" json:"result,omitempty"`
+	Timeout     int64               `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Obfuscate passwords of the form ' password=xxxxx ' from connectionString since
+// connectionStrings are used as metric labels and we don't want to leak passwords
+// Return: the Connection string with the password replaced by '###'
+func (c PostgresCheck) GetEndpoint() string {
+	//looking for a substring that starts with a space,
+	//'password=', then any non-whitespace characters,
+	//until an ending space
+	re := regexp.MustCompile(`\spassword=\S*\s`)
+	return re.ReplaceAllString(c.Connection, " password=### ")
+}
+
+func (c PostgresCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c PostgresCheck) GetName() string {
+	return c.Name
+}
+
+func (c PostgresCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c PostgresCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c PostgresCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c PostgresCheck) GetType() string {
+	return "postgres"
+}
+
+func (c PostgresCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+// This is used to supply a default value for unsupplied fields
+func (c *PostgresCheck) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawPostgresCheck PostgresCheck
+	raw := rawPostgresCheck{
+		Driver: "postgres",
+		Query:  "SELECT 1",
+		Result: 1,
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	*c = PostgresCheck(raw)
+	return nil
+}
+
+type PodCheck struct {
+	Description          string              `yaml:"description" json:"description,omitempty"`
+	Name                 string              `yaml:"name" json:"name,omitempty"`
+	Labels               map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn            []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance          []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Namespace            string              `yaml:"namespace" json:"namespace,omitempty"`
+	Spec                 string              `yaml:"spec" json:"spec,omitempty"`
+	ScheduleTimeout      int64               `yaml:"scheduleTimeout" json:"scheduleTimeout,omitempty"`
+	ReadyTimeout         int64               `yaml:"readyTimeout" json:"readyTimeout,omitempty"`
+	HttpTimeout          int64               `yaml:"httpTimeout" json:"httpTimeout,omitempty"`
+	DeleteTimeout        int64               `yaml:"deleteTimeout" json:"deleteTimeout,omitempty"`
+	IngressTimeout       int64               `yaml:"ingressTimeout" json:"ingressTimeout,omitempty"`
+	HttpRetryInterval    int64               `yaml:"httpRetryInterval" json:"httpRetryInterval,omitempty"`
+	Deadline             int64               `yaml:"deadline" json:"deadline,omitempty"`
+	Port                 int64               `yaml:"port" json:"port,omitempty"`
+	Path                 string              `yaml:"path" json:"path,omitempty"`
+	IngressName          string              `yaml:"ingressName" json:"ingressName,omitempty"`
+	IngressHost          string              `yaml:"ingressHost" json:"ingressHost,omitempty"`
+	ExpectedContent      string              `yaml:"expectedContent" json:"expectedContent,omitempty"`
+	ExpectedHttpStatuses []int               `yaml:"expectedHttpStatuses" json:"expectedHttpStatuses,omitempty"`
+	PriorityClass        string              `yaml:"priorityClass" json:"priorityClass,omitempty"`
+	// IngressClass of the created ingress, the cluster default is used if empty
+	IngressClass string `yaml:"ingressClass,omitempty" json:"ingressClass,omitempty"`
+	// Secret containing the TLS certificate of IngressHost, IngressHost is probed over HTTPS if set
+	IngressTLSSecret string `yaml:"ingressTLSSecret,omitempty" json:"ingressTLSSecret,omitempty"`
+	SkipTLSVerify    bool   `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	// Gateway (namespace/name) to attach a Gateway API HTTPRoute to, instead of creating an ingress
+	Gateway string `yaml:"gateway,omitempty" json:"gateway,omitempty"`
+	Timeout int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c PodCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c PodCheck) GetName() string {
+	return c.Name
+}
+
+func (c PodCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c PodCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c PodCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (p PodCheck) GetEndpoint() string {
+	return p.Name
+}
+
+func (p PodCheck) String() string {
+	return "pod/" + p.Name
+}
+
+func (c PodCheck) GetType() string {
+	return "pod"
+}
+
+func (c PodCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type LDAPCheck struct {
+	Description   string              `yaml:"description" json:"description,omitempty"`
+	Name          string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels        map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn     []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance   []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Host          string              `yaml:"host" json:"host,omitempty"`
+	Username      string              `yaml:"username" json:"username,omitempty"`
+	Password      string              `yaml:"password" json:"password,omitempty"`
+	BindDN        string              `yaml:"bindDN" json:"bindDN,omitempty"`
+	UserSearch    string              `yaml:"userSearch" json:"userSearch,omitempty"`
+	SkipTLSVerify bool                `yaml:"skipTLSVerify" json:"skipTLSVerify,omitempty"`
+	Timeout       int64               `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c LDAPCheck) GetEndpoint() string {
+	return c.Host
+}
+
+func (c LDAPCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c LDAPCheck) GetName() string {
+	return c.Name
+}
+
+func (c LDAPCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c LDAPCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c LDAPCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c LDAPCheck) GetType() string {
+	return "ldap"
+}
+
+func (c LDAPCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type NamespaceCheck struct {
+	Description          string              `yaml:"description" json:"description,omitempty"`
+	CheckName            string              `yaml:"checkName" json:"checkName,omitempty"`
+	Labels               map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn            []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance          []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	NamespaceNamePrefix  string              `yaml:"namespaceNamePrefix" json:"namespaceNamePrefix,omitempty"`
+	NamespaceLabels      map[string]string   `yaml:"namespaceLabels" json:"namespaceLabels,omitempty"`
+	NamespaceAnnotations map[string]string   `yaml:"namespaceAnnotations" json:"namespaceAnnotations,omitempty"`
+	PodSpec              string              `yaml:"podSpec" json:"podSpec,omitempty"`
+	ScheduleTimeout      int64               `yaml:"scheduleTimeout" json:"schedule_timeout,omitempty"`
+	ReadyTimeout         int64               `yaml:"readyTimeout" json:"readyTimeout,omitempty"`
+	HttpTimeout          int64               `yaml:"httpTimeout" json:"httpTimeout,omitempty"`
+	DeleteTimeout        int64               `yaml:"deleteTimeout" json:"deleteTimeout,omitempty"`
+	IngressTimeout       int64               `yaml:"ingressTimeout" json:"ingressTimeout,omitempty"`
+	HttpRetryInterval    int64               `yaml:"httpRetryInterval" json:"httpRetryInterval,omitempty"`
+	Deadline             int64               `yaml:"deadline" json:"deadline,omitempty"`
+	Port                 int64               `yaml:"port" json:"port,omitempty"`
+	Path                 string              `yaml:"path" json:"path,omitempty"`
+	IngressName          string              `yaml:"ingressName" json:"ingressName,omitempty"`
+	IngressHost          string              `yaml:"ingressHost" json:"ingressHost,omitempty"`
+	ExpectedContent      string              `yaml:"expectedContent" json:"expectedContent,omitempty"`
+	ExpectedHttpStatuses []int64             `yaml:"expectedHttpStatuses" json:"expectedHttpStatuses,omitempty"`
+	PriorityClass        string              `yaml:"priorityClass" json:"priorityClass,omitempty"`
+	// IngressClass of the created ingress, the cluster default is used if empty
+	IngressClass string `yaml:"ingressClass,omitempty" json:"ingressClass,omitempty"`
+	// Secret containing the TLS certificate of IngressHost, IngressHost is probed over HTTPS if set
+	IngressTLSSecret string `yaml:"ingressTLSSecret,omitempty" json:"ingressTLSSecret,omitempty"`
+	SkipTLSVerify    bool   `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	// Gateway (namespace/name) to attach a Gateway API HTTPRoute to, instead of creating an ingress
+	Gateway string `yaml:"gateway,omitempty" json:"gateway,omitempty"`
+	Timeout int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c NamespaceCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c NamespaceCheck) GetName() string {
+	return c.CheckName
+}
+
+func (c NamespaceCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c NamespaceCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c NamespaceCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (p NamespaceCheck) GetEndpoint() string {
+	return p.CheckName
+}
+
+func (p NamespaceCheck) String() string {
+	return "namespace/" + p.CheckName
+}
+
+func (c NamespaceCheck) GetType() string {
+	return "namespace"
+}
+
+func (c NamespaceCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type DNSCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Server      string              `yaml:"server" json:"server,omitempty"`
+	Port        int                 `yaml:"port" json:"port,omitempty"`
+	Query       string              `yaml:"query,omitempty" json:"query,omitempty"`
+	QueryType   string              `yaml:"querytype" json:"querytype,omitempty"`
+	MinRecords  int                 `yaml:"minrecords,omitempty" json:"minrecords,omitempty"`
+	ExactReply  []string            `yaml:"exactreply,omitempty" json:"exactreply,omitempty"`
+	Timeout     int                 `yaml:"timeout" json:"timeout,omitempty"`
+	// SrvReply    SrvReply `yaml:"srvReply,omitempty" json:"srvReply,omitempty"`
+}
+
+func (c DNSCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s@%s:%d", c.QueryType, c.Query, c.Server, c.Port)
+}
+
+func (c DNSCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c DNSCheck) GetName() string {
+	return c.Name
+}
+
+func (c DNSCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c DNSCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c DNSCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c DNSCheck) GetType() string {
+	return "dns"
+}
+
+func (c DNSCheck) GetTimeout() int64 {
+	return int64(c.Timeout)
+}
+
+type HelmCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Chartmuseum string              `yaml:"chartmuseum" json:"chartmuseum,omitempty"`
+	Project     string              `yaml:"project,omitempty" json:"project,omitempty"`
+	Username    string              `yaml:"username" json:"username,omitempty"`
+	Password    string              `yaml:"password" json:"password,omitempty"`
+	CaFile      *string             `yaml:"cafile,omitempty" json:"cafile,omitempty"`
+	// Mode is either chartmuseum (default) to push and pull a test chart using the chartmuseum API,
+	// repository to validate the index.yaml of a chart repository and download a chart from it,
+	// oci to push, pull and delete a test chart in an OCI registry, or install to install a chart
+	// from a chart repository into a throwaway namespace and run its tests
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// URL of the chart repository or OCI registry, e.g. https://charts.helm.sh/stable or oci://ghcr.io/flanksource/charts
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+	// Chart to download in repository and install mode, defaults to the first chart in the index
+	Chart string `yaml:"chart,omitempty" json:"chart,omitempty"`
+	// Version of the chart to download in repository and install mode, defaults to the latest version
+	Version       string `yaml:"version,omitempty" json:"version,omitempty"`
+	SkipTLSVerify bool   `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	// Connect to the OCI registry over HTTP instead of HTTPS, only used in oci mode
+	PlainHTTP bool `yaml:"plainHTTP,omitempty" json:"plainHTTP,omitempty"`
+	// Values to install the chart with in install mode, as a values.yaml document
+	Values string `yaml:"values,omitempty" json:"values,omitempty"`
+	// Prefix of the throwaway namespace the chart is installed into, defaults to canary-helm
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// Maximum time in milliseconds to wait for the installed resources to become ready
+	InstallTimeout int64 `yaml:"installTimeout,omitempty" json:"installTimeout,omitempty"`
+	// Maximum time in milliseconds to wait for the helm test hooks to complete
+	TestTimeout int64 `yaml:"testTimeout,omitempty" json:"testTimeout,omitempty"`
+	// Maximum time in milliseconds to wait for the release to be uninstalled
+	UninstallTimeout int64 `yaml:"uninstallTimeout,omitempty" json:"uninstallTimeout,omitempty"`
+	// Number of log lines of every failed test hook pod to include in the result message
+	LogLines int64 `yaml:"logLines,omitempty" json:"logLines,omitempty"`
+	Timeout  int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c HelmCheck) GetEndpoint() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return fmt.Sprintf("%s/%s", c.Chartmuseum, c.Project)
+}
+
+func (c HelmCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c HelmCheck) GetName() string {
+	return c.Name
+}
+
+func (c HelmCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c HelmCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c HelmCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c HelmCheck) GetType() string {
+	return "helm"
+}
+
+func (c HelmCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type SSHCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// Host to connect to, either host or host:port
+	Host string `yaml:"host" json:"host,omitempty"`
+	// Port to connect to, defaults to 22
+	Port     int    `yaml:"port,omitempty" json:"port,omitempty"`
+	Username string `yaml:"username" json:"username,omitempty"`
+	// Password used for password authentication
+	Password VarSource `yaml:"password,omitempty" json:"password,omitempty"`
+	// PEM encoded private key used for public key authentication
+	PrivateKey VarSource `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
+	// Expected SHA256 (SHA256:...) or MD5 (MD5:aa:bb:...) fingerprint of the host key, required unless insecureSkipVerify is set
+	HostKeyFingerprint string `yaml:"hostKeyFingerprint,omitempty" json:"hostKeyFingerprint,omitempty"`
+	// InsecureSkipVerify accepts any host key, leaving the connection open to man in the middle attacks
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty" json:"insecureSkipVerify,omitempty"`
+	// Command to run after logging in, the check only verifies the login if empty
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+	// Expected exit code of the command
+	ExpectedExitCode int `yaml:"expectedExitCode,omitempty" json:"expectedExitCode,omitempty"`
+	// Content expected to be found in the output of the command
+	ExpectedOutput string `yaml:"expectedOutput,omitempty" json:"expectedOutput,omitempty"`
+	Timeout        int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c SSHCheck) GetAddress() string {
+	if _, _, err := net.SplitHostPort(c.Host); err == nil {
+		return c.Host
+	}
+	port := c.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+func (c SSHCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s@%s", c.Username, c.GetAddress())
+}
+
+func (c SSHCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c SSHCheck) GetName() string {
+	return c.Name
+}
+
+func (c SSHCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c SSHCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c SSHCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c SSHCheck) GetType() string {
+	return "ssh"
+}
+
+func (c SSHCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type PrometheusCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// Address of the Prometheus compatible HTTP API, e.g. http://prometheus:9090
+	Host string `yaml:"host" json:"host,omitempty"`
+	// PromQL instant query
+	Query    string `yaml:"query" json:"query,omitempty"`
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	// Password used for basic authentication
+	Password VarSource `yaml:"password,omitempty" json:"password,omitempty"`
+	// Operator used to compare every returned sample against value, one of >, >=, <, <=, ==, !=
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty"`
+	// Value each returned sample is compared against using operator
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+	// Minimum number of series the query must return
+	MinSeries int `yaml:"minSeries,omitempty" json:"minSeries,omitempty"`
+	// Fail if the query returns any series, e.g. for alert style queries
+	MustBeEmpty bool `yaml:"mustBeEmpty,omitempty" json:"mustBeEmpty,omitempty"`
+	// Skip TLS verify when connecting to prometheus
+	SkipTLSVerify bool  `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	Timeout       int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c PrometheusCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", c.Host, c.Query)
+}
+
+func (c PrometheusCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c PrometheusCheck) GetName() string {
+	return c.Name
+}
+
+func (c PrometheusCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c PrometheusCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c PrometheusCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c PrometheusCheck) GetType() string {
+	return "prometheus"
+}
+
+func (c PrometheusCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type KubernetesCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// Kind of the resources to check, e.g. Deployment, StatefulSet, DaemonSet, Node, PersistentVolumeClaim or Certificate
+	Kind string `yaml:"kind" json:"kind,omitempty"`
+	// API version of the kind, only required when the kind is served by multiple API groups, e.g. cert-manager.io/v1alpha2
+	APIVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
+	// Namespace to search, all namespaces are searched if empty
+	Namespace     string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	LabelSelector string `yaml:"labelSelector,omitempty" json:"labelSelector,omitempty"`
+	// Minimum number of resources that must match, defaults to 1
+	MinCount *int `yaml:"minCount,omitempty" json:"minCount,omitempty"`
+	// Require every matched resource to be ready, using the readiness rules of the kind
+	Ready bool `yaml:"ready,omitempty" json:"ready,omitempty"`
+	// Minimum number of ready replicas of every matched workload
+	MinReadyReplicas int64 `yaml:"minReadyReplicas,omitempty" json:"minReadyReplicas,omitempty"`
+	// Conditions every matched resource must have
+	Conditions []KubernetesCondition `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+	// JSONPath expressions evaluated against every matched resource
+	JSONPath []JSONPathAssertion `yaml:"jsonPath,omitempty" json:"jsonPath,omitempty"`
+	Timeout  int64               `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+type KubernetesCondition struct {
+	Type string `yaml:"type" json:"type"`
+	// Expected status of the condition, defaults to True
+	Status string `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+type JSONPathAssertion struct {
+	// JSONPath expression, e.g. {.status.phase}
+	Path string `yaml:"path" json:"path"`
+	// Expected result of the expression, any non empty result is accepted if empty
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+func (c KubernetesCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s?%s", c.Kind, c.Namespace, c.LabelSelector)
+}
+
+func (c KubernetesCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c KubernetesCheck) GetName() string {
+	return c.Name
+}
+
+func (c KubernetesCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c KubernetesCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c KubernetesCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c KubernetesCheck) GetType() string {
+	return "kubernetes"
+}
+
+func (c KubernetesCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type JobCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Namespace   string              `yaml:"namespace" json:"namespace,omitempty"`
+	// Spec of the batch/v1 Job to run, the Job name is used as a prefix for the generated name
+	Spec string `yaml:"spec" json:"spec,omitempty"`
+	// Maximum time in milliseconds to wait for the first pod of the Job to be scheduled, defaults to 60s
+	ScheduleTimeout int64 `yaml:"scheduleTimeout" json:"scheduleTimeout,omitempty"`
+	// Maximum time in milliseconds to wait for the Job to be deleted
+	DeleteTimeout int64 `yaml:"deleteTimeout" json:"deleteTimeout,omitempty"`
+	// Maximum time in milliseconds for the Job to complete, defaults to the interval or 5m if there is none
+	Deadline int64 `yaml:"deadline" json:"deadline,omitempty"`
+	// Number of log lines of every pod of a failed Job to include in the result message
+	LogLines int64 `yaml:"logLines,omitempty" json:"logLines,omitempty"`
+	Timeout  int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c JobCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c JobCheck) GetName() string {
+	return c.Name
+}
+
+func (c JobCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c JobCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c JobCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c JobCheck) GetEndpoint() string {
+	return c.Name
+}
+
+func (c JobCheck) String() string {
+	return "job/" + c.Name
+}
+
+func (c JobCheck) GetType() string {
+	return "job"
+}
+
+func (c JobCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type ConnectivityCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Namespace   string              `yaml:"namespace" json:"namespace,omitempty"`
+	// Only schedule probe pods on nodes matching these labels, defaults to all schedulable nodes
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty" json:"nodeSelector,omitempty"`
+	// Image of the probe pods, it must provide sh, httpd and wget, defaults to busybox
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+	// Port the probe pods listen on, defaults to 8080
+	Port int64 `yaml:"port,omitempty" json:"port,omitempty"`
+	// Skip probing the ClusterIP of the service in front of every probe pod
+	SkipService bool `yaml:"skipService,omitempty" json:"skipService,omitempty"`
+	// Skip probing the DNS name of the service in front of every probe pod
+	SkipDNS bool `yaml:"skipDNS,omitempty" json:"skipDNS,omitempty"`
+	// Maximum time in milliseconds to wait for the probe pods to be running, defaults to 60s
+	ScheduleTimeout int64 `yaml:"scheduleTimeout" json:"scheduleTimeout,omitempty"`
+	// Maximum time in milliseconds of a single probe, defaults to 2000
+	ProbeTimeout  int64  `yaml:"probeTimeout,omitempty" json:"probeTimeout,omitempty"`
+	Deadline      int64  `yaml:"deadline" json:"deadline,omitempty"`
+	PriorityClass string `yaml:"priorityClass" json:"priorityClass,omitempty"`
+	Timeout       int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c ConnectivityCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c ConnectivityCheck) GetName() string {
+	return c.Name
+}
+
+func (c ConnectivityCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c ConnectivityCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c ConnectivityCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c ConnectivityCheck) GetEndpoint() string {
+	return c.Name
+}
+
+func (c ConnectivityCheck) String() string {
+	return "connectivity/" + c.Name
+}
+
+func (c ConnectivityCheck) GetType() string {
+	return "connectivity"
+}
+
+func (c ConnectivityCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type VolumeCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Namespace   string              `yaml:"namespace" json:"namespace,omitempty"`
+	// StorageClass to provision the volume with, the cluster default is used if empty
+	StorageClass string `yaml:"storageClass" json:"storageClass,omitempty"`
+	// Size of the PersistentVolumeClaim, defaults to 1Gi
+	Size string `yaml:"size,omitempty" json:"size,omitempty"`
+	// Access mode of the PersistentVolumeClaim, defaults to ReadWriteOnce
+	AccessMode string `yaml:"accessMode,omitempty" json:"accessMode,omitempty"`
+	// Image of the pod mounting the volume, it must provide sh, dd and md5sum, defaults to busybox
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+	// Size in MB of the file written and read back, defaults to 1
+	WriteSizeMB int64 `yaml:"writeSizeMB,omitempty" json:"writeSizeMB,omitempty"`
+	// Flush the written file to the volume before reading it back
+	Fsync bool `yaml:"fsync,omitempty" json:"fsync,omitempty"`
+	// Maximum time in milliseconds to wait for the volume to be bound, defaults to 60s
+	ProvisionTimeout int64 `yaml:"provisionTimeout" json:"provisionTimeout,omitempty"`
+	// Maximum time in milliseconds to wait for the pod to be running, defaults to 60s
+	ScheduleTimeout int64 `yaml:"scheduleTimeout" json:"scheduleTimeout,omitempty"`
+	// Maximum time in milliseconds to wait for the pod and volume to be deleted
+	DeleteTimeout int64  `yaml:"deleteTimeout" json:"deleteTimeout,omitempty"`
+	Deadline      int64  `yaml:"deadline" json:"deadline,omitempty"`
+	PriorityClass string `yaml:"priorityClass" json:"priorityClass,omitempty"`
+	Timeout       int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c VolumeCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c VolumeCheck) GetName() string {
+	return c.Name
+}
+
+func (c VolumeCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c VolumeCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c VolumeCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c VolumeCheck) GetEndpoint() string {
+	return c.Name
+}
+
+func (c VolumeCheck) String() string {
+	return "volume/" + c.Name
+}
+
+func (c VolumeCheck) GetType() string {
+	return "volume"
+}
+
+func (c VolumeCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+// SwiftConnection holds the credentials of an OpenStack Swift object store
+type SwiftConnection struct {
+	// Keystone or TempAuth URL, e.g. https://keystone.example.com:5000/v3
+	AuthURL  string `yaml:"authURL" json:"authURL,omitempty"`
+	Username string `yaml:"username" json:"username,omitempty"`
+	// Password or API key of the user
+	APIKey string `yaml:"apiKey" json:"apiKey,omitempty"`
+	// Domain of the user, only used with v3 authentication
+	Domain string `yaml:"domain,omitempty" json:"domain,omitempty"`
+	// Tenant (project) to scope the token to
+	Tenant string `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+	// Region of the object store endpoint, defaults to the first region
+	Region        string `yaml:"region,omitempty" json:"region,omitempty"`
+	SkipTLSVerify bool   `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+}
+
+type SwiftCheck struct {
+	Description     string              `yaml:"description" json:"description,omitempty"`
+	Name            string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels          map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn       []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance     []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	SwiftConnection `yaml:",inline" json:",inline"`
+	Container       string `yaml:"container" json:"container,omitempty"`
+	ObjectPath      string `yaml:"objectPath" json:"objectPath,omitempty"`
+	// Size of the random object to upload as a quantity, e.g. 10Mi, defaults to 16 bytes
+	ObjectSize string `yaml:"objectSize,omitempty" json:"objectSize,omitempty"`
+	Timeout    int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c SwiftCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c SwiftCheck) GetName() string {
+	return c.Name
+}
+
+func (c SwiftCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c SwiftCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c SwiftCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c SwiftCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", c.AuthURL, c.Container)
+}
+
+func (c SwiftCheck) GetType() string {
+	return "swift"
+}
+
+func (c SwiftCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type SwiftContainerCheck struct {
+	Description     string              `yaml:"description" json:"description,omitempty"`
+	Name            string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels          map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn       []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance     []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	SwiftConnection `yaml:",inline" json:",inline"`
+	Container       string `yaml:"container" json:"container,omitempty"`
+	// regular expression to restrict matches to a subset
+	ObjectPath string `yaml:"objectPath" json:"objectPath,omitempty"`
+	// maximum allowed age of matched objects in seconds
+	MaxAge int64 `yaml:"maxAge" json:"maxAge,omitempty"`
+	// min size of of most recent matched object in bytes
+	MinSize int64 `yaml:"minSize" json:"minSize,omitempty"`
+	Timeout int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c SwiftContainerCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c SwiftContainerCheck) GetName() string {
+	return c.Name
+}
+
+func (c SwiftContainerCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c SwiftContainerCheck) GetDependsOn() []string {
+	return c.DependsOn
+}
+
+func (c SwiftContainerCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
+}
+
+func (c SwiftContainerCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", c.AuthURL, c.Container)
+}
+
+func (c SwiftContainerCheck) GetType() string {
+	return "swiftContainer"
+}
+
+func (c SwiftContainerCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+// AzureConnection holds the shared key credentials of an Azure storage account
+type AzureConnection struct {
+	Account    string `yaml:"account" json:"account,omitempty"`
+	AccountKey string `yaml:"accountKey" json:"accountKey,omitempty"`
+	// Blob service endpoint, defaults to https://<account>.blob.core.windows.net.
+	// Emulators such as azurite use http://127.0.0.1:10000/<account>
+	Endpoint      string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	SkipTLSVerify bool   `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+}
+
+// BlobEndpoint returns the endpoint of the blob service of the account
+func (c AzureConnection) BlobEndpoint() string {
+	if c.Endpoint != "" {
+		return strings.TrimSuffix(c.Endpoint, "/")
+	}
+	return fmt.Sprintf("https://%s.blob.core.windows.net", c.Account)
+}
+
+type AzureBlobCheck struct {
+	Description     string              `yaml:"description" json:"description,omitempty"`
+	Name            string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels          map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn       []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance     []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	AzureConnection `yaml:",inline" json:",inline"`
+	Container       string `yaml:"container" json:"container,omitempty"`
+	ObjectPath      string `yaml:"objectPath" json:"objectPath,omitempty"`
+	// Size of the random blob to upload as a quantity, e.g. 10Mi, defaults to 16 bytes
+	ObjectSize string `yaml:"objectSize,omitempty" json:"objectSize,omitempty"`
+	Timeout    int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c AzureBlobCheck) GetDescription() string {
+	return c.Description
+}
+
+func (c AzureBlobCheck) GetName() string {
+	return c.Name
+}
+
+func (c AzureBlobCheck) GetLabels() map[string]string {
+	return c.Labels
+}
+
+func (c AzureBlobCheck) GetDependsOn() []string {
+	return c.DependsOn
 }
 
-func (c S3Check) GetEndpoint() string {
-	return fmt.Sprintf("%s/%s", c.Bucket.Endpoint, c.Bucket.Name)
+func (c AzureBlobCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
 }
 
-func (c S3Check) GetDescription() string {
-	return c.Description
+func (c AzureBlobCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", c.BlobEndpoint(), c.Container)
 }
 
-func (c S3Check) GetType() string {
-	return "s3"
+func (c AzureBlobCheck) GetType() string {
+	return "azureBlob"
 }
 
-type S3BucketCheck struct {
-	Description string `yaml:"description" json:"description,omitempty"`
-	Bucket      string `yaml:"bucket" json:"bucket,omitempty"`
-	AccessKey   string `yaml:"accessKey" json:"accessKey,omitempty"`
-	SecretKey   string `yaml:"secretKey" json:"secretKey,omitempty"`
-	Region      string `yaml:"region" json:"region,omitempty"`
-	Endpoint    string `yaml:"endpoint" json:"endpoint,omitempty"`
-	// glob path to restrict matches to a subset
+func (c AzureBlobCheck) GetTimeout() int64 {
+	return c.Timeout
+}
+
+type AzureContainerCheck struct {
+	Description     string              `yaml:"description" json:"description,omitempty"`
+	Name            string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels          map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn       []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance     []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	AzureConnection `yaml:",inline" json:",inline"`
+	Container       string `yaml:"container" json:"container,omitempty"`
+	// regular expression to restrict matches to a subset
 	ObjectPath string `yaml:"objectPath" json:"objectPath,omitempty"`
-	ReadWrite  bool   `yaml:"readWrite" json:"readWrite,omitempty"`
-	// maximum allowed age of matched objects in seconds
+	// maximum allowed age of matched blobs in seconds
 	MaxAge int64 `yaml:"maxAge" json:"maxAge,omitempty"`
-	// min size of of most recent matched object in bytes
+	// min size of of most recent matched blob in bytes
 	MinSize int64 `yaml:"minSize" json:"minSize,omitempty"`
-	// Use path style path: http://s3.amazonaws.com/BUCKET/KEY instead of http://BUCKET.s3.amazonaws.com/KEY
-	UsePathStyle bool `yaml:"usePathStyle" json:"usePathStyle,omitempty"`
-	// Skip TLS verify when connecting to s3
-	SkipTLSVerify bool `yaml:"skipTLSVerify" json:"skipTLSVerify,omitempty"`
+	Timeout int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
-func (s3 S3BucketCheck) GetEndpoint() string {
-	return fmt.Sprintf("%s/%s", s3.Endpoint, s3.Bucket)
+func (c AzureContainerCheck) GetDescription() string {
+	return c.Description
 }
 
-func (c S3BucketCheck) GetDescription() string {
-	return c.Description
+func (c AzureContainerCheck) GetName() string {
+	return c.Name
 }
 
-func (c S3BucketCheck) GetType() string {
-	return "s3Bucket"
+func (c AzureContainerCheck) GetLabels() map[string]string {
+	return c.Labels
 }
 
-type DockerPullCheck struct {
-	Description    string `yaml:"description" json:"description,omitempty"`
-	Image          string `yaml:"image" json:"image,omitempty"`
-	Username       string `yaml:"username" json:"username,omitempty"`
-	Password       string `yaml:"password" json:"password,omitempty"`
-	ExpectedDigest string `yaml:"expectedDigest" json:"expectedDigest,omitempty"`
-	ExpectedSize   int64  `yaml:"expectedSize" json:"expectedSize,omitempty"`
+func (c AzureContainerCheck) GetDependsOn() []string {
+	return c.DependsOn
 }
 
-func (c DockerPullCheck) GetEndpoint() string {
-	return c.Image
+func (c AzureContainerCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
 }
 
-func (c DockerPullCheck) GetDescription() string {
-	return c.Description
+func (c AzureContainerCheck) GetEndpoint() string {
+	return fmt.Sprintf("%s/%s", c.BlobEndpoint(), c.Container)
 }
 
-func (c DockerPullCheck) GetType() string {
-	return "dockerPull"
+func (c AzureContainerCheck) GetType() string {
+	return "azureContainer"
 }
 
-type DockerPushCheck struct {
-	Description string `yaml:"description" json:"description,omitempty"`
-	Image       string `yaml:"image" json:"image,omitempty"`
-	Username    string `yaml:"username" json:"username,omitempty"`
-	Password    string `yaml:"password" json:"password,omitempty"`
+func (c AzureContainerCheck) GetTimeout() int64 {
+	return c.Timeout
 }
 
-func (c DockerPushCheck) GetEndpoint() string {
-	return c.Image
+type FilesystemCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// File, directory or glob pattern to scan, e.g. /mnt/backups/*.tar.gz. Directories are
+	// expanded to the files they contain
+	Path string `yaml:"path" json:"path,omitempty"`
+	// Include files in sub directories of matched directories
+	Recursive bool `yaml:"recursive,omitempty" json:"recursive,omitempty"`
+	// Minimum number of matched files
+	MinCount int64 `yaml:"minCount,omitempty" json:"minCount,omitempty"`
+	// Maximum number of matched files
+	MaxCount int64 `yaml:"maxCount,omitempty" json:"maxCount,omitempty"`
+	// maximum allowed age of the most recent file in seconds
+	MaxAge int64 `yaml:"maxAge,omitempty" json:"maxAge,omitempty"`
+	// min size of the most recent file in bytes
+	MinSize int64 `yaml:"minSize,omitempty" json:"minSize,omitempty"`
+	// Expected sha256 checksum of the most recent file
+	SHA256 string `yaml:"sha256,omitempty" json:"sha256,omitempty"`
+	// Regular expression the content of the most recent file must match
+	ContentRegex string `yaml:"contentRegex,omitempty" json:"contentRegex,omitempty"`
+	Timeout      int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
-func (c DockerPushCheck) GetDescription() string {
+func (c FilesystemCheck) GetDescription() string {
 	return c.Description
 }
 
-func (c DockerPushCheck) GetType() string {
-	return "dockerPush"
+func (c FilesystemCheck) GetName() string {
+	return c.Name
 }
 
-type PostgresCheck struct {
-	Description string `yaml:"description" json:"description,omitempty"`
-	Driver      string `yaml:"driver" json:"driver,omitempty"`
-	Connection  string `yaml:"connection" json:"connection,omitempty"`
-	Query       string `yaml:"query" json:"query,omitempty"`
-	Result      int    `yaml:"results" json:"result,omitempty"`
+func (c FilesystemCheck) GetLabels() map[string]string {
+	return c.Labels
 }
 
-// Obfuscate passwords of the form ' password=xxxxx ' from connectionString since
-// connectionStrings are used as metric labels and we don't want to leak passwords
-// Return: the Connection string with the password replaced by '###'
-func (c PostgresCheck) GetEndpoint() string {
-	//looking for a substring that starts with a space,
-	//'password=', then any non-whitespace characters,
-	//until an ending space
-	re := regexp.MustCompile(`\spassword=\S*\s`)
-	return re.ReplaceAllString(c.Connection, " password=### ")
+func (c FilesystemCheck) GetDependsOn() []string {
+	return c.DependsOn
 }
 
-func (c PostgresCheck) GetDescription() string {
-	return c.Description
+func (c FilesystemCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
 }
 
-func (c PostgresCheck) GetType() string {
-	return "postgres"
+func (c FilesystemCheck) GetEndpoint() string {
+	return c.Path
 }
 
-// This is used to supply a default value for unsupplied fields
-func (c *PostgresCheck) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	type rawPostgresCheck PostgresCheck
-	raw := rawPostgresCheck{
-		Driver: "postgres",
-		Query:  "SELECT 1",
-		Result: 1,
-	}
-	if err := unmarshal(&raw); err != nil {
-		return err
-	}
-
-	*c = PostgresCheck(raw)
-	return nil
+func (c FilesystemCheck) GetType() string {
+	return "filesystem"
 }
 
-type PodCheck struct {
-	Description          string `yaml:"description" json:"description,omitempty"`
-	Name                 string `yaml:"name" json:"name,omitempty"`
-	Namespace            string `yaml:"namespace" json:"namespace,omitempty"`
-	Spec                 string `yaml:"spec" json:"spec,omitempty"`
-	ScheduleTimeout      int64  `yaml:"scheduleTimeout" json:"scheduleTimeout,omitempty"`
-	ReadyTimeout         int64  `yaml:"readyTimeout" json:"readyTimeout,omitempty"`
-	HttpTimeout          int64  `yaml:"httpTimeout" json:"httpTimeout,omitempty"`
-	DeleteTimeout        int64  `yaml:"deleteTimeout" json:"deleteTimeout,omitempty"`
-	IngressTimeout       int64  `yaml:"ingressTimeout" json:"ingressTimeout,omitempty"`
-	HttpRetryInterval    int64  `yaml:"httpRetryInterval" json:"httpRetryInterval,omitempty"`
-	Deadline             int64  `yaml:"deadline" json:"deadline,omitempty"`
-	Port                 int64  `yaml:"port" json:"port,omitempty"`
-	Path                 string `yaml:"path" json:"path,omitempty"`
-	IngressName          string `yaml:"ingressName" json:"ingressName,omitempty"`
-	IngressHost          string `yaml:"ingressHost" json:"ingressHost,omitempty"`
-	ExpectedContent      string `yaml:"expectedContent" json:"expectedContent,omitempty"`
-	ExpectedHttpStatuses []int  `yaml:"expectedHttpStatuses" json:"expectedHttpStatuses,omitempty"`
-	PriorityClass        string `yaml:"priorityClass" json:"priorityClass,omitempty"`
+func (c FilesystemCheck) GetTimeout() int64 {
+	return c.Timeout
 }
 
-func (c PodCheck) GetDescription() string {
-	return c.Description
+type CertificateCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// Namespaces to scan for kubernetes.io/tls secrets, all namespaces are scanned if empty.
+	// Secrets are not scanned if only paths are configured
+	Namespaces []string `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+	// Label selector of the secrets to scan
+	LabelSelector string `yaml:"labelSelector,omitempty" json:"labelSelector,omitempty"`
+	// Glob patterns of PEM encoded certificate files to scan
+	Paths []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+	// Fail if any certificate expires within this number of days
+	ExpiryDays int64 `yaml:"expiryDays,omitempty" json:"expiryDays,omitempty"`
+	// PEM file with additional root certificates to verify chains with, besides the
+	// system roots and the ca.crt of every secret
+	CAFile string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	// Only check expiry, without verifying the certificate chains
+	SkipChainVerify bool  `yaml:"skipChainVerify,omitempty" json:"skipChainVerify,omitempty"`
+	Timeout         int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
-func (p PodCheck) GetEndpoint() string {
-	return p.Name
+// ScanSecrets returns true if kubernetes.io/tls secrets should be scanned
+func (c CertificateCheck) ScanSecrets() bool {
+	return len(c.Paths) == 0 || len(c.Namespaces) > 0 || c.LabelSelector != ""
 }
 
-func (p PodCheck) String() string {
-	return "pod/" + p.Name
+func (c CertificateCheck) GetDescription() string {
+	return c.Description
 }
 
-func (c PodCheck) GetType() string {
-	return "pod"
+func (c CertificateCheck) GetName() string {
+	return c.Name
 }
 
-type LDAPCheck struct {
-	Description   string `yaml:"description" json:"description,omitempty"`
-	Host          string `yaml:"host" json:"host,omitempty"`
-	Username      string `yaml:"username" json:"username,omitempty"`
-	Password      string `yaml:"password" json:"password,omitempty"`
-	BindDN        string `yaml:"bindDN" json:"bindDN,omitempty"`
-	UserSearch    string `yaml:"userSearch" json:"userSearch,omitempty"`
-	SkipTLSVerify bool   `yaml:"skipTLSVerify" json:"skipTLSVerify,omitempty"`
+func (c CertificateCheck) GetLabels() map[string]string {
+	return c.Labels
 }
 
-func (c LDAPCheck) GetEndpoint() string {
-	return c.Host
+func (c CertificateCheck) GetDependsOn() []string {
+	return c.DependsOn
 }
 
-func (c LDAPCheck) GetDescription() string {
-	return c.Description
+func (c CertificateCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
 }
 
-func (c LDAPCheck) GetType() string {
-	return "ldap"
+func (c CertificateCheck) GetEndpoint() string {
+	return c.Name
 }
 
-type NamespaceCheck struct {
-	Description          string            `yaml:"description" json:"description,omitempty"`
-	CheckName            string            `yaml:"checkName" json:"checkName,omitempty"`
-	NamespaceNamePrefix  string            `yaml:"namespaceNamePrefix" json:"namespaceNamePrefix,omitempty"`
-	NamespaceLabels      map[string]string `yaml:"namespaceLabels" json:"namespaceLabels,omitempty"`
-	NamespaceAnnotations map[string]string `yaml:"namespaceAnnotations" json:"namespaceAnnotations,omitempty"`
-	PodSpec              string            `yaml:"podSpec" json:"podSpec,omitempty"`
-	ScheduleTimeout      int64             `yaml:"scheduleTimeout" json:"schedule_timeout,omitempty"`
-	ReadyTimeout         int64             `yaml:"readyTimeout" json:"readyTimeout,omitempty"`
-	HttpTimeout          int64             `yaml:"httpTimeout" json:"httpTimeout,omitempty"`
-	DeleteTimeout        int64             `yaml:"deleteTimeout" json:"deleteTimeout,omitempty"`
-	IngressTimeout       int64             `yaml:"ingressTimeout" json:"ingressTimeout,omitempty"`
-	HttpRetryInterval    int64             `yaml:"httpRetryInterval" json:"httpRetryInterval,omitempty"`
-	Deadline             int64             `yaml:"deadline" json:"deadline,omitempty"`
-	Port                 int64             `yaml:"port" json:"port,omitempty"`
-	Path                 string            `yaml:"path" json:"path,omitempty"`
-	IngressName          string            `yaml:"ingressName" json:"ingressName,omitempty"`
-	IngressHost          string            `yaml:"ingressHost" json:"ingressHost,omitempty"`
-	ExpectedContent      string            `yaml:"expectedContent" json:"expectedContent,omitempty"`
-	ExpectedHttpStatuses []int64           `yaml:"expectedHttpStatuses" json:"expectedHttpStatuses,omitempty"`
-	PriorityClass        string            `yaml:"priorityClass" json:"priorityClass,omitempty"`
+func (c CertificateCheck) String() string {
+	return "certificate/" + c.Name
 }
 
-func (c NamespaceCheck) GetDescription() string {
-	return c.Description
+func (c CertificateCheck) GetType() string {
+	return "certificate"
 }
 
-func (p NamespaceCheck) GetEndpoint() string {
-	return p.CheckName
+func (c CertificateCheck) GetTimeout() int64 {
+	return c.Timeout
 }
 
-func (p NamespaceCheck) String() string {
-	return "namespace/" + p.CheckName
+type OIDCCheck struct {
+	Description string              `yaml:"description" json:"description,omitempty"`
+	Name        string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string            `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// Issuer URL, the discovery document is fetched from <issuer>/.well-known/openid-configuration
+	Issuer       string    `yaml:"issuer" json:"issuer,omitempty"`
+	ClientID     string    `yaml:"clientID" json:"clientID,omitempty"`
+	ClientSecret VarSource `yaml:"clientSecret" json:"clientSecret,omitempty"`
+	// GrantType is either client_credentials (default) or password
+	GrantType string    `yaml:"grantType,omitempty" json:"grantType,omitempty"`
+	Username  string    `yaml:"username,omitempty" json:"username,omitempty"`
+	Password  VarSource `yaml:"password,omitempty" json:"password,omitempty"`
+	Scopes    []string  `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// Audience expected in the aud claim of the token, defaults to the client id. It is also
+	// sent as the audience parameter of client credentials token requests
+	Audience string `yaml:"audience,omitempty" json:"audience,omitempty"`
+	// Fail if the key that signed the token expires within this number of days and no other
+	// signing key is published to replace it. Only keys with an x5c certificate chain expire
+	KeyExpiryDays int64 `yaml:"keyExpiryDays,omitempty" json:"keyExpiryDays,omitempty"`
+	SkipTLSVerify bool  `yaml:"skipTLSVerify,omitempty" json:"skipTLSVerify,omitempty"`
+	Timeout       int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
-func (c NamespaceCheck) GetType() string {
-	return "namespace"
+// ExpectedAudience returns the audience the token must be issued for
+func (c OIDCCheck) ExpectedAudience() string {
+	if c.Audience != "" {
+		return c.Audience
+	}
+	return c.ClientID
 }
 
-type DNSCheck struct {
-	Description string   `yaml:"description" json:"description,omitempty"`
-	Server      string   `yaml:"server" json:"server,omitempty"`
-	Port        int      `yaml:"port" json:"port,omitempty"`
-	Query       string   `yaml:"query,omitempty" json:"query,omitempty"`
-	QueryType   string   `yaml:"querytype" json:"querytype,omitempty"`
-	MinRecords  int      `yaml:"minrecords,omitempty" json:"minrecords,omitempty"`
-	ExactReply  []string `yaml:"exactreply,omitempty" json:"exactreply,omitempty"`
-	Timeout     int      `yaml:"timeout" json:"timeout,omitempty"`
-	// SrvReply    SrvReply `yaml:"srvReply,omitempty" json:"srvReply,omitempty"`
+func (c OIDCCheck) GetDescription() string {
+	return c.Description
 }
 
-func (c DNSCheck) GetEndpoint() string {
-	return fmt.Sprintf("%s/%s@%s:%d", c.QueryType, c.Query, c.Server, c.Port)
+func (c OIDCCheck) GetName() string {
+	return c.Name
 }
 
-func (c DNSCheck) GetDescription() string {
-	return c.Description
+func (c OIDCCheck) GetLabels() map[string]string {
+	return c.Labels
 }
 
-func (c DNSCheck) GetType() string {
-	return "dns"
+func (c OIDCCheck) GetDependsOn() []string {
+	return c.DependsOn
 }
 
-type HelmCheck struct {
-	Description string  `yaml:"description" json:"description,omitempty"`
-	Chartmuseum string  `yaml:"chartmuseum" json:"chartmuseum,omitempty"`
-	Project     string  `yaml:"project,omitempty" json:"project,omitempty"`
-	Username    string  `yaml:"username" json:"username,omitempty"`
-	Password    string  `yaml:"password" json:"password,omitempty"`
-	CaFile      *string `yaml:"cafile,omitempty" json:"cafile,omitempty"`
+func (c OIDCCheck) GetMaintenance() []MaintenanceWindow {
+	return c.Maintenance
 }
 
-func (c HelmCheck) GetEndpoint() string {
-	return fmt.Sprintf("%s/%s", c.Chartmuseum, c.Project)
+func (c OIDCCheck) GetEndpoint() string {
+	return c.Issuer
 }
 
-func (c HelmCheck) GetDescription() string {
-	return c.Description
+func (c OIDCCheck) GetType() string {
+	return "oidc"
 }
 
-func (c HelmCheck) GetType() string {
-	return "helm"
+func (c OIDCCheck) GetTimeout() int64 {
+	return c.Timeout
 }
 
 /*
@@ -416,11 +1586,38 @@ docker:
     expectedSize: 1219782
 ```
 
+Set `mode: registry` to resolve the tag to a manifest digest using the registry API directly,
+without a docker daemon. `verifyBlobs` additionally downloads every layer and verifies its digest.
+
+```yaml
+
+docker:
+  - image: docker.io/library/busybox:1.31.1
+    mode: registry
+    verifyBlobs: true
+```
+
 */
 type DockerPull struct {
 	DockerPullCheck `yaml:",inline" json:"inline"`
 }
 
+/*
+This check will push an existing image using the docker daemon. With `mode: registry` it will instead:
+
+* generate an image with a small unique layer and push it under a timestamped tag of the image repository
+* pull the image back and verify its digest
+* delete the image again using the registry API
+
+```yaml
+
+dockerPush:
+  - image: ttl.sh/flanksource-canary
+    mode: registry
+    username: $DOCKER_USERNAME
+    password: $DOCKER_PASSWORD
+```
+*/
 type DockerPush struct {
 	DockerPushCheck `yaml:",inline" json:"inline"`
 }
@@ -429,8 +1626,10 @@ type DockerPush struct {
 This check will:
 
 * list objects in the bucket to check for Read permissions
-* PUT an object into the bucket for Write permissions
-* download previous uploaded object to check for Get permissions
+* PUT an object into the bucket for Write permissions, using a multipart upload for objects larger than `partSize`
+* download previous uploaded object to check for Get permissions and verify its checksum
+* DELETE the object and confirm it is no longer returned
+* optionally assert that versioning and object lock are enabled on the bucket
 
 ```yaml
 
@@ -442,6 +1641,9 @@ s3:
     secretKey: "<access-key>"
     accessKey: "<secret-key>"
     objectPath: "path/to/object"
+    objectSize: 20Mi
+    partSize: 5Mi
+    checksum: sha256
 ```
 */
 type S3 struct {
@@ -454,6 +1656,11 @@ This check will
 - search objects matching the provided object path pattern
 - check that latest object is no older than provided MaxAge value in seconds
 - check that latest object size is not smaller than provided MinSize value in bytes.
+- check that latest object is not more than MaxSizeDrop percent smaller than the previous object.
+
+Objects can be grouped by a capture group of the object path, e.g. one group per database,
+in which case the checks are applied to the latest object of every group and the failing
+groups are reported by name.
 
 ```yaml
 s3Bucket:
@@ -466,6 +1673,15 @@ s3Bucket:
     readWrite: true
     maxAge: 5000000
     minSize: 50000
+  - bucket: backups
+    accessKey: "<access-key>"
+    secretKey: "<secret-key>"
+    region: "us-east-2"
+    endpoint: "https://s3.us-east-2.amazonaws.com"
+    objectPath: "pg/backups/(?P<db>[^/]+)/.*\\.sql\\.gz$"
+    groupBy: db
+    maxAge: 86400
+    maxSizeDrop: 50
 ```
 */
 type S3Bucket struct {
@@ -497,6 +1713,7 @@ pod:
     path: /foo/bar
     ingressName: hello-world-golang
     ingressHost: "hello-world-golang.127.0.0.1.nip.io"
+    ingressClass: nginx
     scheduleTimeout: 2000
     readyTimeout: 5000
     httpTimeout: 2000
@@ -591,10 +1808,359 @@ type Postgres struct {
 	PostgresCheck `yaml:",inline" json:"inline"`
 }
 
+/*
+This check will push a test chart to a chartmuseum project, pull it back and delete it.
+Plain chart repositories are checked with `mode: repository`, which validates the index.yaml
+and downloads a chart, while OCI registries are checked with `mode: oci` which pushes, pulls
+and deletes a test chart. With `mode: install` the chart is installed into a throwaway namespace,
+its `helm test` hooks are run once all resources are ready and it is uninstalled again.
+
+```yaml
+
+helm:
+  - chartmuseum: https://harbor.example.com
+    project: library
+    username: admin
+    password: ""
+  - mode: repository
+    url: https://flanksource.github.io/charts
+    chart: canary-checker
+  - mode: oci
+    url: oci://harbor.example.com/library
+    username: admin
+    password: ""
+  - mode: install
+    url: https://stefanprodan.github.io/podinfo
+    chart: podinfo
+    values: |
+      replicaCount: 1
+    installTimeout: 300000
+```
+*/
 type Helm struct {
 	HelmCheck `yaml:",inline" json:"inline"`
 }
 
+/*
+The SSH check will:
+
+* connect to the host using a password and/or private key
+* verify the host key against the pinned fingerprint
+* run the command (if specified) and verify its exit code and output
+
+```yaml
+
+ssh:
+  - host: bastion.example.com
+    port: 22
+    username: canary
+    privateKey:
+      value: $(SSH_PRIVATE_KEY)
+    hostKeyFingerprint: "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"
+    command: "uptime"
+    expectedExitCode: 0
+    expectedOutput: "load average"
+```
+*/
+type SSH struct {
+	SSHCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+The Prometheus check will run an instant PromQL query and:
+
+* verify that at least minSeries series are returned
+* or verify that no series are returned when mustBeEmpty is set
+* compare the value of every returned series using operator and value
+
+The labels of series failing the comparison are included in the check message.
+
+```yaml
+
+prometheus:
+  - host: http://prometheus-k8s.monitoring:9090
+    query: sum(rate(http_requests_total{code=~"5.."}[5m])) by (service) / sum(rate(http_requests_total[5m])) by (service)
+    operator: "<"
+    value: "0.05"
+    minSeries: 1
+  - host: http://prometheus-k8s.monitoring:9090
+    query: ALERTS{severity="critical", alertstate="firing"}
+    mustBeEmpty: true
+```
+*/
+type Prometheus struct {
+	PrometheusCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+The Kubernetes check selects existing resources by kind, namespace and label selector and verifies
+that every matched resource:
+
+* is ready, e.g. all replicas of a Deployment are ready, a Node is Ready or a PersistentVolumeClaim is Bound
+* has at least minReadyReplicas ready replicas
+* has the listed conditions
+* matches the JSONPath expressions
+
+```yaml
+
+kubernetes:
+  - kind: Deployment
+    namespace: kube-system
+    labelSelector: k8s-app=kube-dns
+    ready: true
+    minReadyReplicas: 2
+  - kind: Node
+    ready: true
+    minCount: 3
+  - kind: Certificate
+    apiVersion: cert-manager.io/v1alpha2
+    namespace: ingress-nginx
+    conditions:
+      - type: Ready
+  - kind: PersistentVolumeClaim
+    namespace: monitoring
+    jsonPath:
+      - path: "{.status.phase}"
+        value: Bound
+```
+*/
+type Kubernetes struct {
+	KubernetesCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+The Job check creates a Job from the embedded spec and waits for it to run to completion,
+the Job and its pods are always deleted afterwards.
+
+```yaml
+job:
+  - name: smoke-test
+    namespace: default
+    spec: |
+      apiVersion: batch/v1
+      kind: Job
+      metadata:
+        name: smoke-test
+      spec:
+        template:
+          spec:
+            containers:
+              - name: smoke-test
+                image: busybox
+                command: ["nslookup", "kubernetes.default"]
+            restartPolicy: Never
+    scheduleTimeout: 10000
+    deadline: 60000
+    logLines: 20
+```
+*/
+type Job struct {
+	JobCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+The Connectivity check schedules a probe pod on every matching node, and verifies that every
+probe pod can reach every other probe pod using its pod IP, the ClusterIP of its service and
+the DNS name of its service. Failing node pairs are reported as a matrix.
+
+```yaml
+connectivity:
+  - name: cni
+    namespace: default
+    nodeSelector:
+      node-role.kubernetes.io/worker: ""
+    scheduleTimeout: 20000
+    probeTimeout: 2000
+    deadline: 60000
+```
+*/
+type Connectivity struct {
+	ConnectivityCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+The Volume check provisions a PersistentVolumeClaim with the given StorageClass and mounts it in a pod,
+which writes a file to the volume and reads it back. The pod and the claim are always deleted afterwards.
+
+```yaml
+volume:
+  - name: standard
+    namespace: default
+    storageClass: standard
+    size: 1Gi
+    writeSizeMB: 10
+    fsync: true
+    provisionTimeout: 60000
+    scheduleTimeout: 60000
+    deleteTimeout: 60000
+    deadline: 180000
+```
+*/
+type Volume struct {
+	VolumeCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will:
+
+* list objects in the container to check for Read permissions
+* PUT an object into the container for Write permissions
+* download previous uploaded object to check for Get permissions and verify its checksum
+* DELETE the object and confirm it is no longer returned
+
+```yaml
+
+swift:
+  - authURL: https://keystone.example.com:5000/v3
+    username: canary
+    apiKey: "<password>"
+    domain: default
+    tenant: canary
+    container: canary
+    objectPath: path/to/object
+```
+*/
+type Swift struct {
+	SwiftCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will query the contents of a container for freshness and size, useful for verifying backups have been created
+
+```yaml
+
+swiftContainer:
+  - authURL: https://keystone.example.com:5000/v3
+    username: canary
+    apiKey: "<password>"
+    domain: default
+    tenant: canary
+    container: backups
+    objectPath: "mysql/.*\\.sql\\.gz"
+    maxAge: 86400
+    minSize: 1024
+```
+*/
+type SwiftContainer struct {
+	SwiftContainerCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will:
+
+* list blobs in the container to check for Read permissions
+* PUT a blob into the container for Write permissions
+* download previous uploaded blob to check for Get permissions and verify its checksum
+* DELETE the blob and confirm it is no longer returned
+
+```yaml
+
+azureBlob:
+  - account: devstoreaccount1
+    accountKey: "<account-key>"
+    endpoint: http://127.0.0.1:10000/devstoreaccount1
+    container: canary
+    objectPath: path/to/object
+```
+*/
+type AzureBlob struct {
+	AzureBlobCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will query the contents of a container for freshness and size, useful for verifying backups have been created
+
+```yaml
+
+azureContainer:
+  - account: backups
+    accountKey: "<account-key>"
+    container: backups
+    objectPath: "mysql/.*\\.sql\\.gz"
+    maxAge: 86400
+    minSize: 1024
+```
+*/
+type AzureContainer struct {
+	AzureContainerCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will scan files matching a path or glob pattern, e.g. output files of batch jobs
+on a shared volume, and check:
+
+- the number of matched files is within minCount and maxCount
+- the most recent file is no older than maxAge seconds
+- the most recent file is not smaller than minSize bytes
+- the sha256 checksum or the content of the most recent file
+
+```yaml
+
+filesystem:
+  - path: /mnt/reports/*.csv
+    minCount: 1
+    maxAge: 86400
+    minSize: 1024
+    contentRegex: "^date,"
+```
+*/
+type Filesystem struct {
+	FilesystemCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will parse the certificates of kubernetes.io/tls secrets matching a label selector
+and of PEM files on disk, and fail if any of them expires within `expiryDays` or does not
+chain up to a trusted root. The number of days until every certificate expires is exported
+as the `canary_check_certificate_expiry` gauge.
+
+```yaml
+
+certificate:
+  - name: ingress-certificates
+    namespaces: [default, ingress-nginx]
+    labelSelector: app.kubernetes.io/managed-by=cert-manager
+    expiryDays: 14
+  - name: etcd-certificates
+    paths:
+      - /etc/kubernetes/pki/etcd/*.crt
+    caFile: /etc/kubernetes/pki/etcd/ca.crt
+    expiryDays: 30
+```
+*/
+type Certificate struct {
+	CertificateCheck `yaml:",inline" json:"inline"`
+}
+
+/*
+This check will fetch the OpenID Connect discovery document and JSON Web Key Set of an identity
+provider, request a token using the client credentials or password grant and verify the signature,
+issuer, audience and expiry of the returned JWT. The number of days until every signing key with a
+certificate chain expires is exported as the `canary_check_oidc_key_expiry` gauge.
+
+```yaml
+oidc:
+  - issuer: https://sso.example.com/auth/realms/master
+    clientID: canary-checker
+    clientSecret:
+      secretKeyRef:
+        name: oidc-client
+        key: secret
+    scopes: [openid]
+    keyExpiryDays: 14
+  - issuer: https://sso.example.com/auth/realms/apps
+    clientID: grafana
+    grantType: password
+    username: canary
+    password:
+      value: $(OIDC_PASSWORD)
+    audience: account
+```
+*/
+type OIDC struct {
+	OIDCCheck `yaml:",inline" json:"inline"`
+}
+
 type SrvReply struct {
 	Target   string `yaml:"target,omitempty"`
 	Port     int    `yaml:"port,omitempty"`
@@ -18,15 +18,15 @@ type VarSource struct {
 	// Selects a field of the pod: supports metadata.name, metadata.namespace, metadata.labels, metadata.annotations,
 	// spec.nodeName, spec.serviceAccountName, status.hostIP, status.podIP, status.podIPs.
 	// +optional
-	FieldRef *corev1.ObjectFieldSelector `json:"fieldRef,omitempty" protobuf:"bytes,1,opt,name=fieldRef"`
+	FieldRef *corev1.ObjectFieldSelector `yaml:"fieldRef,omitempty" json:"fieldRef,omitempty" protobuf:"bytes,1,opt,name=fieldRef"`
 	// +optional
-	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+	Value string `yaml:"value,omitempty" json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
 	// Selects a key of a ConfigMap.
 	// +optional
-	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty" protobuf:"bytes,3,opt,name=configMapKeyRef"`
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `yaml:"configMapKeyRef,omitempty" json:"configMapKeyRef,omitempty" protobuf:"bytes,3,opt,name=configMapKeyRef"`
 	// Selects a key of a secret in the pod's namespace
 	// +optional
-	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty" protobuf:"bytes,4,opt,name=secretKeyRef"`
+	SecretKeyRef *corev1.SecretKeySelector `yaml:"secretKeyRef,omitempty" json:"secretKeyRef,omitempty" protobuf:"bytes,4,opt,name=secretKeyRef"`
 }
 
 // getSecretRefValue returns the value of a secret in the supplied namespace
@@ -145,6 +145,11 @@ func formatMap(m map[string]string) (fmtStr string) {
 	return
 }
 
+// IsResolved returns true if the value does not need to be looked up from a reference
+func (from VarSource) IsResolved() bool {
+	return from.Value != "" || (from.FieldRef == nil && from.ConfigMapKeyRef == nil && from.SecretKeyRef == nil)
+}
+
 // GetEnvVarRefValue returns the value referenced by the supplied EnvVarSource given the other supplied information.
 func GetEnvVarRefValue(kc kubernetes.Interface, ns string, from *VarSource, obj runtime.Object) (string, error) {
 	if from.Value != "" {
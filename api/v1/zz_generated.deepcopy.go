@@ -21,9 +21,123 @@ limitations under the License.
 package v1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureBlob) DeepCopyInto(out *AzureBlob) {
+	*out = *in
+	in.AzureBlobCheck.DeepCopyInto(&out.AzureBlobCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureBlob.
+func (in *AzureBlob) DeepCopy() *AzureBlob {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureBlob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureBlobCheck) DeepCopyInto(out *AzureBlobCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	out.AzureConnection = in.AzureConnection
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureBlobCheck.
+func (in *AzureBlobCheck) DeepCopy() *AzureBlobCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureBlobCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureConnection) DeepCopyInto(out *AzureConnection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureConnection.
+func (in *AzureConnection) DeepCopy() *AzureConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureContainer) DeepCopyInto(out *AzureContainer) {
+	*out = *in
+	in.AzureContainerCheck.DeepCopyInto(&out.AzureContainerCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureContainer.
+func (in *AzureContainer) DeepCopy() *AzureContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureContainerCheck) DeepCopyInto(out *AzureContainerCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	out.AzureConnection = in.AzureConnection
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureContainerCheck.
+func (in *AzureContainerCheck) DeepCopy() *AzureContainerCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureContainerCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bucket) DeepCopyInto(out *Bucket) {
 	*out = *in
@@ -101,6 +215,13 @@ func (in *CanaryList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make(map[string]VarSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.HTTP != nil {
 		in, out := &in.HTTP, &out.HTTP
 		*out = make([]HTTPCheck, len(*in))
@@ -118,22 +239,30 @@ func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	if in.DockerPull != nil {
 		in, out := &in.DockerPull, &out.DockerPull
 		*out = make([]DockerPullCheck, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.DockerPush != nil {
 		in, out := &in.DockerPush, &out.DockerPush
 		*out = make([]DockerPushCheck, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.S3 != nil {
 		in, out := &in.S3, &out.S3
 		*out = make([]S3Check, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.S3Bucket != nil {
 		in, out := &in.S3Bucket, &out.S3Bucket
 		*out = make([]S3BucketCheck, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.TCP != nil {
 		in, out := &in.TCP, &out.TCP
@@ -150,7 +279,9 @@ func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	if in.LDAP != nil {
 		in, out := &in.LDAP, &out.LDAP
 		*out = make([]LDAPCheck, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.SSL != nil {
 		in, out := &in.SSL, &out.SSL
@@ -160,12 +291,16 @@ func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	if in.ICMP != nil {
 		in, out := &in.ICMP, &out.ICMP
 		*out = make([]ICMPCheck, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Postgres != nil {
 		in, out := &in.Postgres, &out.Postgres
 		*out = make([]PostgresCheck, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Helm != nil {
 		in, out := &in.Helm, &out.Helm
@@ -181,6 +316,109 @@ func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = make([]SSHCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = make([]PrometheusCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = make([]KubernetesCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = make([]JobCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Connectivity != nil {
+		in, out := &in.Connectivity, &out.Connectivity
+		*out = make([]ConnectivityCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = make([]VolumeCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Swift != nil {
+		in, out := &in.Swift, &out.Swift
+		*out = make([]SwiftCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SwiftContainer != nil {
+		in, out := &in.SwiftContainer, &out.SwiftContainer
+		*out = make([]SwiftContainerCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AzureBlob != nil {
+		in, out := &in.AzureBlob, &out.AzureBlob
+		*out = make([]AzureBlobCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AzureContainer != nil {
+		in, out := &in.AzureContainer, &out.AzureContainer
+		*out = make([]AzureContainerCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Filesystem != nil {
+		in, out := &in.Filesystem, &out.Filesystem
+		*out = make([]FilesystemCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = make([]CertificateCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = make([]OIDCCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySpec.
@@ -226,6 +464,64 @@ func (in *CanaryStatus) DeepCopy() *CanaryStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Certificate) DeepCopyInto(out *Certificate) {
+	*out = *in
+	in.CertificateCheck.DeepCopyInto(&out.CertificateCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Certificate.
+func (in *Certificate) DeepCopy() *Certificate {
+	if in == nil {
+		return nil
+	}
+	out := new(Certificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateCheck) DeepCopyInto(out *CertificateCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateCheck.
+func (in *CertificateCheck) DeepCopy() *CertificateCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -242,6 +538,61 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Connectivity) DeepCopyInto(out *Connectivity) {
+	*out = *in
+	in.ConnectivityCheck.DeepCopyInto(&out.ConnectivityCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Connectivity.
+func (in *Connectivity) DeepCopy() *Connectivity {
+	if in == nil {
+		return nil
+	}
+	out := new(Connectivity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheck) DeepCopyInto(out *ConnectivityCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheck.
+func (in *ConnectivityCheck) DeepCopy() *ConnectivityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNS) DeepCopyInto(out *DNS) {
 	*out = *in
@@ -261,6 +612,23 @@ func (in *DNS) DeepCopy() *DNS {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSCheck) DeepCopyInto(out *DNSCheck) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExactReply != nil {
 		in, out := &in.ExactReply, &out.ExactReply
 		*out = make([]string, len(*in))
@@ -281,7 +649,7 @@ func (in *DNSCheck) DeepCopy() *DNSCheck {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerPull) DeepCopyInto(out *DockerPull) {
 	*out = *in
-	out.DockerPullCheck = in.DockerPullCheck
+	in.DockerPullCheck.DeepCopyInto(&out.DockerPullCheck)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerPull.
@@ -297,12 +665,29 @@ func (in *DockerPull) DeepCopy() *DockerPull {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerPullCheck) DeepCopyInto(out *DockerPullCheck) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerPullCheck.
-func (in *DockerPullCheck) DeepCopy() *DockerPullCheck {
-	if in == nil {
-		return nil
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerPullCheck.
+func (in *DockerPullCheck) DeepCopy() *DockerPullCheck {
+	if in == nil {
+		return nil
 	}
 	out := new(DockerPullCheck)
 	in.DeepCopyInto(out)
@@ -312,7 +697,7 @@ func (in *DockerPullCheck) DeepCopy() *DockerPullCheck {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerPush) DeepCopyInto(out *DockerPush) {
 	*out = *in
-	out.DockerPushCheck = in.DockerPushCheck
+	in.DockerPushCheck.DeepCopyInto(&out.DockerPushCheck)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerPush.
@@ -320,413 +705,1132 @@ func (in *DockerPush) DeepCopy() *DockerPush {
 	if in == nil {
 		return nil
 	}
-	out := new(DockerPush)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DockerPushCheck) DeepCopyInto(out *DockerPushCheck) {
-	*out = *in
+	out := new(DockerPush)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerPushCheck) DeepCopyInto(out *DockerPushCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerPushCheck.
+func (in *DockerPushCheck) DeepCopy() *DockerPushCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerPushCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filesystem) DeepCopyInto(out *Filesystem) {
+	*out = *in
+	in.FilesystemCheck.DeepCopyInto(&out.FilesystemCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filesystem.
+func (in *Filesystem) DeepCopy() *Filesystem {
+	if in == nil {
+		return nil
+	}
+	out := new(Filesystem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemCheck) DeepCopyInto(out *FilesystemCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemCheck.
+func (in *FilesystemCheck) DeepCopy() *FilesystemCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTP) DeepCopyInto(out *HTTP) {
+	*out = *in
+	in.HTTPCheck.DeepCopyInto(&out.HTTPCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTP.
+func (in *HTTP) DeepCopy() *HTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPCheck) DeepCopyInto(out *HTTPCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseCodes != nil {
+		in, out := &in.ResponseCodes, &out.ResponseCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheck.
+func (in *HTTPCheck) DeepCopy() *HTTPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Helm) DeepCopyInto(out *Helm) {
+	*out = *in
+	in.HelmCheck.DeepCopyInto(&out.HelmCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Helm.
+func (in *Helm) DeepCopy() *Helm {
+	if in == nil {
+		return nil
+	}
+	out := new(Helm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmCheck) DeepCopyInto(out *HelmCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.CaFile != nil {
+		in, out := &in.CaFile, &out.CaFile
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmCheck.
+func (in *HelmCheck) DeepCopy() *HelmCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ICMP) DeepCopyInto(out *ICMP) {
+	*out = *in
+	in.ICMPCheck.DeepCopyInto(&out.ICMPCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ICMP.
+func (in *ICMP) DeepCopy() *ICMP {
+	if in == nil {
+		return nil
+	}
+	out := new(ICMP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ICMPCheck) DeepCopyInto(out *ICMPCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ICMPCheck.
+func (in *ICMPCheck) DeepCopy() *ICMPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ICMPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPathAssertion) DeepCopyInto(out *JSONPathAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPathAssertion.
+func (in *JSONPathAssertion) DeepCopy() *JSONPathAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPathAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Job) DeepCopyInto(out *Job) {
+	*out = *in
+	in.JobCheck.DeepCopyInto(&out.JobCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Job.
+func (in *Job) DeepCopy() *Job {
+	if in == nil {
+		return nil
+	}
+	out := new(Job)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobCheck) DeepCopyInto(out *JobCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobCheck.
+func (in *JobCheck) DeepCopy() *JobCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(JobCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kubernetes) DeepCopyInto(out *Kubernetes) {
+	*out = *in
+	in.KubernetesCheck.DeepCopyInto(&out.KubernetesCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubernetes.
+func (in *Kubernetes) DeepCopy() *Kubernetes {
+	if in == nil {
+		return nil
+	}
+	out := new(Kubernetes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesCheck) DeepCopyInto(out *KubernetesCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinCount != nil {
+		in, out := &in.MinCount, &out.MinCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]KubernetesCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.JSONPath != nil {
+		in, out := &in.JSONPath, &out.JSONPath
+		*out = make([]JSONPathAssertion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesCheck.
+func (in *KubernetesCheck) DeepCopy() *KubernetesCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesCondition) DeepCopyInto(out *KubernetesCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesCondition.
+func (in *KubernetesCondition) DeepCopy() *KubernetesCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAP) DeepCopyInto(out *LDAP) {
+	*out = *in
+	in.LDAPCheck.DeepCopyInto(&out.LDAPCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAP.
+func (in *LDAP) DeepCopy() *LDAP {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPCheck) DeepCopyInto(out *LDAPCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPCheck.
+func (in *LDAPCheck) DeepCopy() *LDAPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Namespace) DeepCopyInto(out *Namespace) {
+	*out = *in
+	in.NamespaceCheck.DeepCopyInto(&out.NamespaceCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Namespace.
+func (in *Namespace) DeepCopy() *Namespace {
+	if in == nil {
+		return nil
+	}
+	out := new(Namespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceCheck) DeepCopyInto(out *NamespaceCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NamespaceAnnotations != nil {
+		in, out := &in.NamespaceAnnotations, &out.NamespaceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedHttpStatuses != nil {
+		in, out := &in.ExpectedHttpStatuses, &out.ExpectedHttpStatuses
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceCheck.
+func (in *NamespaceCheck) DeepCopy() *NamespaceCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDC) DeepCopyInto(out *OIDC) {
+	*out = *in
+	in.OIDCCheck.DeepCopyInto(&out.OIDCCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
+func (in *OIDC) DeepCopy() *OIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCCheck) DeepCopyInto(out *OIDCCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	in.Password.DeepCopyInto(&out.Password)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCCheck.
+func (in *OIDCCheck) DeepCopy() *OIDCCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pod) DeepCopyInto(out *Pod) {
+	*out = *in
+	in.PodCheck.DeepCopyInto(&out.PodCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pod.
+func (in *Pod) DeepCopy() *Pod {
+	if in == nil {
+		return nil
+	}
+	out := new(Pod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodCheck) DeepCopyInto(out *PodCheck) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpectedHttpStatuses != nil {
+		in, out := &in.ExpectedHttpStatuses, &out.ExpectedHttpStatuses
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerPushCheck.
-func (in *DockerPushCheck) DeepCopy() *DockerPushCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodCheck.
+func (in *PodCheck) DeepCopy() *PodCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(DockerPushCheck)
+	out := new(PodCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTP) DeepCopyInto(out *HTTP) {
+func (in *Postgres) DeepCopyInto(out *Postgres) {
 	*out = *in
-	in.HTTPCheck.DeepCopyInto(&out.HTTPCheck)
+	in.PostgresCheck.DeepCopyInto(&out.PostgresCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTP.
-func (in *HTTP) DeepCopy() *HTTP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Postgres.
+func (in *Postgres) DeepCopy() *Postgres {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTP)
+	out := new(Postgres)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPCheck) DeepCopyInto(out *HTTPCheck) {
+func (in *PostgresCheck) DeepCopyInto(out *PostgresCheck) {
 	*out = *in
-	if in.ResponseCodes != nil {
-		in, out := &in.ResponseCodes, &out.ResponseCodes
-		*out = make([]int, len(*in))
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheck.
-func (in *HTTPCheck) DeepCopy() *HTTPCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresCheck.
+func (in *PostgresCheck) DeepCopy() *PostgresCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPCheck)
+	out := new(PostgresCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Helm) DeepCopyInto(out *Helm) {
+func (in *Prometheus) DeepCopyInto(out *Prometheus) {
 	*out = *in
-	in.HelmCheck.DeepCopyInto(&out.HelmCheck)
+	in.PrometheusCheck.DeepCopyInto(&out.PrometheusCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Helm.
-func (in *Helm) DeepCopy() *Helm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prometheus.
+func (in *Prometheus) DeepCopy() *Prometheus {
 	if in == nil {
 		return nil
 	}
-	out := new(Helm)
+	out := new(Prometheus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HelmCheck) DeepCopyInto(out *HelmCheck) {
+func (in *PrometheusCheck) DeepCopyInto(out *PrometheusCheck) {
 	*out = *in
-	if in.CaFile != nil {
-		in, out := &in.CaFile, &out.CaFile
-		*out = new(string)
-		**out = **in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	in.Password.DeepCopyInto(&out.Password)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmCheck.
-func (in *HelmCheck) DeepCopy() *HelmCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusCheck.
+func (in *PrometheusCheck) DeepCopy() *PrometheusCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(HelmCheck)
+	out := new(PrometheusCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ICMP) DeepCopyInto(out *ICMP) {
+func (in *S3) DeepCopyInto(out *S3) {
 	*out = *in
-	out.ICMPCheck = in.ICMPCheck
+	in.S3Check.DeepCopyInto(&out.S3Check)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ICMP.
-func (in *ICMP) DeepCopy() *ICMP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3.
+func (in *S3) DeepCopy() *S3 {
 	if in == nil {
 		return nil
 	}
-	out := new(ICMP)
+	out := new(S3)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ICMPCheck) DeepCopyInto(out *ICMPCheck) {
+func (in *S3Bucket) DeepCopyInto(out *S3Bucket) {
 	*out = *in
+	in.S3BucketCheck.DeepCopyInto(&out.S3BucketCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ICMPCheck.
-func (in *ICMPCheck) DeepCopy() *ICMPCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Bucket.
+func (in *S3Bucket) DeepCopy() *S3Bucket {
 	if in == nil {
 		return nil
 	}
-	out := new(ICMPCheck)
+	out := new(S3Bucket)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAP) DeepCopyInto(out *LDAP) {
+func (in *S3BucketCheck) DeepCopyInto(out *S3BucketCheck) {
 	*out = *in
-	out.LDAPCheck = in.LDAPCheck
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAP.
-func (in *LDAP) DeepCopy() *LDAP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BucketCheck.
+func (in *S3BucketCheck) DeepCopy() *S3BucketCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAP)
+	out := new(S3BucketCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPCheck) DeepCopyInto(out *LDAPCheck) {
+func (in *S3Check) DeepCopyInto(out *S3Check) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	out.Bucket = in.Bucket
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPCheck.
-func (in *LDAPCheck) DeepCopy() *LDAPCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Check.
+func (in *S3Check) DeepCopy() *S3Check {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPCheck)
+	out := new(S3Check)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Namespace) DeepCopyInto(out *Namespace) {
+func (in *SSH) DeepCopyInto(out *SSH) {
 	*out = *in
-	in.NamespaceCheck.DeepCopyInto(&out.NamespaceCheck)
+	in.SSHCheck.DeepCopyInto(&out.SSHCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Namespace.
-func (in *Namespace) DeepCopy() *Namespace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSH.
+func (in *SSH) DeepCopy() *SSH {
 	if in == nil {
 		return nil
 	}
-	out := new(Namespace)
+	out := new(SSH)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceCheck) DeepCopyInto(out *NamespaceCheck) {
+func (in *SSHCheck) DeepCopyInto(out *SSHCheck) {
 	*out = *in
-	if in.NamespaceLabels != nil {
-		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.NamespaceAnnotations != nil {
-		in, out := &in.NamespaceAnnotations, &out.NamespaceAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ExpectedHttpStatuses != nil {
-		in, out := &in.ExpectedHttpStatuses, &out.ExpectedHttpStatuses
-		*out = make([]int64, len(*in))
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
 		copy(*out, *in)
 	}
+	in.Password.DeepCopyInto(&out.Password)
+	in.PrivateKey.DeepCopyInto(&out.PrivateKey)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceCheck.
-func (in *NamespaceCheck) DeepCopy() *NamespaceCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHCheck.
+func (in *SSHCheck) DeepCopy() *SSHCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceCheck)
+	out := new(SSHCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Pod) DeepCopyInto(out *Pod) {
+func (in *SSL) DeepCopyInto(out *SSL) {
 	*out = *in
-	in.PodCheck.DeepCopyInto(&out.PodCheck)
+	out.SSLCheck = in.SSLCheck
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pod.
-func (in *Pod) DeepCopy() *Pod {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSL.
+func (in *SSL) DeepCopy() *SSL {
 	if in == nil {
 		return nil
 	}
-	out := new(Pod)
+	out := new(SSL)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodCheck) DeepCopyInto(out *PodCheck) {
+func (in *SSLCheck) DeepCopyInto(out *SSLCheck) {
 	*out = *in
-	if in.ExpectedHttpStatuses != nil {
-		in, out := &in.ExpectedHttpStatuses, &out.ExpectedHttpStatuses
-		*out = make([]int, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodCheck.
-func (in *PodCheck) DeepCopy() *PodCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLCheck.
+func (in *SSLCheck) DeepCopy() *SSLCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(PodCheck)
+	out := new(SSLCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Postgres) DeepCopyInto(out *Postgres) {
+func (in *SrvReply) DeepCopyInto(out *SrvReply) {
 	*out = *in
-	out.PostgresCheck = in.PostgresCheck
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Postgres.
-func (in *Postgres) DeepCopy() *Postgres {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SrvReply.
+func (in *SrvReply) DeepCopy() *SrvReply {
 	if in == nil {
 		return nil
 	}
-	out := new(Postgres)
+	out := new(SrvReply)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresCheck) DeepCopyInto(out *PostgresCheck) {
+func (in *Swift) DeepCopyInto(out *Swift) {
 	*out = *in
+	in.SwiftCheck.DeepCopyInto(&out.SwiftCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresCheck.
-func (in *PostgresCheck) DeepCopy() *PostgresCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Swift.
+func (in *Swift) DeepCopy() *Swift {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresCheck)
+	out := new(Swift)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3) DeepCopyInto(out *S3) {
+func (in *SwiftCheck) DeepCopyInto(out *SwiftCheck) {
 	*out = *in
-	out.S3Check = in.S3Check
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	out.SwiftConnection = in.SwiftConnection
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3.
-func (in *S3) DeepCopy() *S3 {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftCheck.
+func (in *SwiftCheck) DeepCopy() *SwiftCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(S3)
+	out := new(SwiftCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3Bucket) DeepCopyInto(out *S3Bucket) {
+func (in *SwiftConnection) DeepCopyInto(out *SwiftConnection) {
 	*out = *in
-	out.S3BucketCheck = in.S3BucketCheck
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Bucket.
-func (in *S3Bucket) DeepCopy() *S3Bucket {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftConnection.
+func (in *SwiftConnection) DeepCopy() *SwiftConnection {
 	if in == nil {
 		return nil
 	}
-	out := new(S3Bucket)
+	out := new(SwiftConnection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3BucketCheck) DeepCopyInto(out *S3BucketCheck) {
+func (in *SwiftContainer) DeepCopyInto(out *SwiftContainer) {
 	*out = *in
+	in.SwiftContainerCheck.DeepCopyInto(&out.SwiftContainerCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BucketCheck.
-func (in *S3BucketCheck) DeepCopy() *S3BucketCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainer.
+func (in *SwiftContainer) DeepCopy() *SwiftContainer {
 	if in == nil {
 		return nil
 	}
-	out := new(S3BucketCheck)
+	out := new(SwiftContainer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3Check) DeepCopyInto(out *S3Check) {
+func (in *SwiftContainerCheck) DeepCopyInto(out *SwiftContainerCheck) {
 	*out = *in
-	out.Bucket = in.Bucket
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	out.SwiftConnection = in.SwiftConnection
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Check.
-func (in *S3Check) DeepCopy() *S3Check {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerCheck.
+func (in *SwiftContainerCheck) DeepCopy() *SwiftContainerCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(S3Check)
+	out := new(SwiftContainerCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SSL) DeepCopyInto(out *SSL) {
+func (in *TCP) DeepCopyInto(out *TCP) {
 	*out = *in
-	out.SSLCheck = in.SSLCheck
+	out.TCPCheck = in.TCPCheck
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSL.
-func (in *SSL) DeepCopy() *SSL {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCP.
+func (in *TCP) DeepCopy() *TCP {
 	if in == nil {
 		return nil
 	}
-	out := new(SSL)
+	out := new(TCP)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SSLCheck) DeepCopyInto(out *SSLCheck) {
+func (in *TCPCheck) DeepCopyInto(out *TCPCheck) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLCheck.
-func (in *SSLCheck) DeepCopy() *SSLCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPCheck.
+func (in *TCPCheck) DeepCopy() *TCPCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(SSLCheck)
+	out := new(TCPCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SrvReply) DeepCopyInto(out *SrvReply) {
+func (in *VarSource) DeepCopyInto(out *VarSource) {
 	*out = *in
+	if in.FieldRef != nil {
+		in, out := &in.FieldRef, &out.FieldRef
+		*out = new(corev1.ObjectFieldSelector)
+		**out = **in
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SrvReply.
-func (in *SrvReply) DeepCopy() *SrvReply {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VarSource.
+func (in *VarSource) DeepCopy() *VarSource {
 	if in == nil {
 		return nil
 	}
-	out := new(SrvReply)
+	out := new(VarSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TCP) DeepCopyInto(out *TCP) {
+func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
-	out.TCPCheck = in.TCPCheck
+	in.VolumeCheck.DeepCopyInto(&out.VolumeCheck)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCP.
-func (in *TCP) DeepCopy() *TCP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Volume.
+func (in *Volume) DeepCopy() *Volume {
 	if in == nil {
 		return nil
 	}
-	out := new(TCP)
+	out := new(Volume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TCPCheck) DeepCopyInto(out *TCPCheck) {
+func (in *VolumeCheck) DeepCopyInto(out *VolumeCheck) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPCheck.
-func (in *TCPCheck) DeepCopy() *TCPCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCheck.
+func (in *VolumeCheck) DeepCopy() *VolumeCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(TCPCheck)
+	out := new(VolumeCheck)
 	in.DeepCopyInto(out)
 	return out
 }
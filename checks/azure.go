@@ -0,0 +1,324 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+)
+
+// version of the blob service REST API, supported by azurite as well
+const azureStorageVersion = "2019-12-12"
+
+type AzureBlobChecker struct{}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *AzureBlobChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.AzureBlob {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Type: returns checker type
+func (c *AzureBlobChecker) Type() string {
+	return "azureBlob"
+}
+
+func (c *AzureBlobChecker) Check(ctx context.Context, check v1.AzureBlobCheck) *pkg.CheckResult {
+	size, err := parseObjectSize(check.ObjectSize, defaultObjectSize)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid objectSize")
+	}
+	store, err := newAzureStore(ctx, check.AzureConnection, check.Container)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid accountKey")
+	}
+	return checkObjectStore(check, store, check.ObjectPath, size, map[string]string{"container": check.Container})
+}
+
+type AzureContainerChecker struct{}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *AzureContainerChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.AzureContainer {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Type: returns checker type
+func (c *AzureContainerChecker) Type() string {
+	return "azureContainer"
+}
+
+func (c *AzureContainerChecker) Check(ctx context.Context, check v1.AzureContainerCheck) *pkg.CheckResult {
+	store, err := newAzureStore(ctx, check.AzureConnection, check.Container)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid accountKey")
+	}
+	return scanObjects(check, store, check.ObjectPath, check.MaxAge, check.MinSize, map[string]string{"container": check.Container})
+}
+
+// azureStore implements objectStore for a container of an Azure storage account,
+// authenticating requests to the blob service REST API with the account shared key
+type azureStore struct {
+	ctx       context.Context
+	client    *http.Client
+	account   string
+	key       []byte
+	endpoint  string
+	container string
+}
+
+type azureBlobList struct {
+	Blobs []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			LastModified  string `xml:"Last-Modified"`
+			ContentLength int64  `xml:"Content-Length"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+type azureError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func newAzureStore(ctx context.Context, connection v1.AzureConnection, container string) (*azureStore, error) {
+	key, err := base64.StdEncoding.DecodeString(connection.AccountKey)
+	if err != nil {
+		return nil, err
+	}
+	return &azureStore{
+		ctx: ctx,
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: connection.SkipTLSVerify},
+			},
+		},
+		account:   connection.Account,
+		key:       key,
+		endpoint:  connection.BlobEndpoint(),
+		container: container,
+	}, nil
+}
+
+func (s *azureStore) List() ([]storedObject, error) {
+	var objects []storedObject
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := s.do("GET", "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, azureResponseError(resp, body)
+		}
+		list := azureBlobList{}
+		if err := xml.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("invalid blob list: %v", err)
+		}
+		for _, blob := range list.Blobs {
+			lastModified, err := http.ParseTime(blob.Properties.LastModified)
+			if err != nil {
+				return nil, fmt.Errorf("invalid last modified time of %s: %v", blob.Name, err)
+			}
+			objects = append(objects, storedObject{Name: blob.Name, Size: blob.Properties.ContentLength, LastModified: lastModified})
+		}
+		if list.NextMarker == "" {
+			return objects, nil
+		}
+		marker = list.NextMarker
+	}
+}
+
+func (s *azureStore) Put(name string, data []byte) error {
+	sum := md5.Sum(data)
+	resp, err := s.do("PUT", name, nil, data, map[string]string{
+		"x-ms-blob-type": "BlockBlob",
+		"Content-Type":   "application/octet-stream",
+		// the service rejects the upload if the checksum of the received content does not match
+		"Content-MD5": base64.StdEncoding.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		return err
+	}
+	return s.expect(resp, http.StatusCreated)
+}
+
+func (s *azureStore) Get(name string) ([]byte, error) {
+	resp, err := s.do("GET", name, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, azureResponseError(resp, body)
+	}
+	return body, nil
+}
+
+func (s *azureStore) Delete(name string) error {
+	resp, err := s.do("DELETE", name, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return s.expect(resp, http.StatusAccepted)
+}
+
+func (s *azureStore) Exists(name string) (bool, error) {
+	resp, err := s.do("HEAD", name, nil, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected response: %s", resp.Status)
+}
+
+func (s *azureStore) expect(resp *http.Response, status int) error {
+	defer resp.Body.Close()
+	if resp.StatusCode == status {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	return azureResponseError(resp, body)
+}
+
+func azureResponseError(resp *http.Response, body []byte) error {
+	e := azureError{}
+	if err := xml.Unmarshal(body, &e); err != nil || e.Code == "" {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return fmt.Errorf("%s: %s %s", resp.Status, e.Code, strings.SplitN(e.Message, "\n", 2)[0])
+}
+
+// do sends a request for the container, or for blob if not empty, signed with the account key
+func (s *azureStore) do(method, blob string, query url.Values, body []byte, headers map[string]string) (*http.Response, error) {
+	u := s.endpoint + "/" + url.PathEscape(s.container)
+	if blob != "" {
+		var segments []string
+		for _, segment := range strings.Split(blob, "/") {
+			segments = append(segments, url.PathEscape(segment))
+		}
+		u += "/" + strings.Join(segments, "/")
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		req.Body = nil
+		req.ContentLength = 0
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureStorageVersion)
+	req.Header.Set("Authorization", "SharedKey "+s.account+":"+s.sign(req))
+	return s.client.Do(req.WithContext(s.ctx))
+}
+
+// sign returns the shared key signature of req as described in
+// https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func (s *azureStore) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	lines := []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	}
+
+	var msHeaders []string
+	for name := range req.Header {
+		if name := strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			msHeaders = append(msHeaders, name)
+		}
+	}
+	sort.Strings(msHeaders)
+	for _, name := range msHeaders {
+		lines = append(lines, name+":"+strings.TrimSpace(req.Header.Get(name)))
+	}
+
+	resource := "/" + s.account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	var params []string
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		values := query[name]
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(name) + ":" + strings.Join(values, ",")
+	}
+	lines = append(lines, resource)
+
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,266 @@
+package checks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	canaryv1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var certificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "canary_check_certificate_expiry",
+		Help: "The number of days until certificate expiration",
+	},
+	[]string{"namespace", "secret", "file", "subject"},
+)
+
+func init() {
+	prometheus.MustRegister(certificateExpiry)
+}
+
+type CertificateChecker struct {
+	k8s *kubernetes.Clientset
+	mtx sync.Mutex
+	// series are the label values of the expiry series set by the last run of every check
+	series map[string]map[string][]string
+}
+
+// certificateBundle is a chain of certificates read from a secret or a file
+type certificateBundle struct {
+	Namespace string
+	Secret    string
+	File      string
+	// Certificates in the order they appear in, the first one is the leaf
+	Certificates []*x509.Certificate
+	// Roots from the ca.crt of the secret
+	Roots []*x509.Certificate
+}
+
+func (b certificateBundle) String() string {
+	if b.File != "" {
+		return b.File
+	}
+	return b.Namespace + "/" + b.Secret
+}
+
+// removeStaleSeries deletes the expiry series of certificates that the previous run of a check found,
+// but which are gone or rotated since
+func (c *CertificateChecker) removeStaleSeries(name string, series map[string][]string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key, values := range c.series[name] {
+		if _, found := series[key]; !found {
+			certificateExpiry.DeleteLabelValues(values...)
+		}
+	}
+	c.series[name] = series
+}
+
+func NewCertificateChecker() *CertificateChecker {
+	cc := &CertificateChecker{series: make(map[string]map[string][]string)}
+
+	k8sClient, err := pkg.NewK8sClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return cc
+	}
+	cc.k8s = k8sClient
+	return cc
+}
+
+// Type: returns checker type
+func (c *CertificateChecker) Type() string {
+	return "certificate"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *CertificateChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.Certificate {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : Parse the certificates of every matching secret and file, verifying their expiry and chain
+// Returns check result and metrics
+func (c *CertificateChecker) Check(ctx context.Context, check canaryv1.CertificateCheck) *pkg.CheckResult {
+	timer := NewTimer()
+	var bundles []certificateBundle
+	var failures []string
+
+	if check.ScanSecrets() {
+		if c.k8s == nil {
+			return unexpectedErrorf(check, fmt.Errorf("connection to k8s not established"), "cannot connect to API server")
+		}
+		secrets, err := c.secretBundles(ctx, check)
+		if err != nil {
+			return unexpectedErrorf(check, err, "failed to list secrets")
+		}
+		bundles = append(bundles, secrets...)
+	}
+	for _, pattern := range check.Paths {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return invalidErrorf(check, err, "invalid path %s", pattern)
+		}
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return Failf(check, "cancelled: %v", err)
+			}
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", file, err))
+				continue
+			}
+			bundles = append(bundles, certificateBundle{File: file, Certificates: parseCertificates(data)})
+		}
+	}
+
+	var roots []*x509.Certificate
+	if check.CAFile != "" {
+		data, err := ioutil.ReadFile(check.CAFile)
+		if err != nil {
+			return invalidErrorf(check, err, "failed to read %s", check.CAFile)
+		}
+		roots = parseCertificates(data)
+	}
+
+	var count int
+	var earliest *x509.Certificate
+	series := make(map[string][]string)
+	for _, bundle := range bundles {
+		if len(bundle.Certificates) == 0 {
+			failures = append(failures, fmt.Sprintf("%s: no certificates found", bundle))
+			continue
+		}
+		for _, cert := range bundle.Certificates {
+			count++
+			days := time.Until(cert.NotAfter).Hours() / 24
+			values := []string{bundle.Namespace, bundle.Secret, bundle.File, cert.Subject.String()}
+			certificateExpiry.WithLabelValues(values...).Set(days)
+			series[strings.Join(values, "\x00")] = values
+			if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+				earliest = cert
+			}
+			if days < float64(check.ExpiryDays) {
+				failures = append(failures, fmt.Sprintf("%s: %s expires in %.1f days", bundle, cert.Subject, days))
+			}
+		}
+		if !check.SkipChainVerify {
+			if err := verifyChain(bundle, roots); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: invalid chain: %v", bundle, err))
+			}
+		}
+	}
+
+	c.removeStaleSeries(pkg.CheckName(check), series)
+
+	result := &pkg.CheckResult{
+		Check:    check,
+		Pass:     true,
+		Duration: int64(timer.Elapsed()),
+		Metrics: []pkg.Metric{
+			{Name: "certificate_count", Type: metrics.GaugeType, Value: float64(count)},
+		},
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return failWithMetrics(result, "%d failures: %s", len(failures), strings.Join(failures, ", "))
+	}
+	if earliest == nil {
+		return failWithMetrics(result, "could not find any certificates")
+	}
+	result.Message = fmt.Sprintf("certificates=%d earliestExpiry=%s (%s)", count, age(time.Until(earliest.NotAfter)), earliest.Subject)
+	return result
+}
+
+func (c *CertificateChecker) secretBundles(ctx context.Context, check canaryv1.CertificateCheck) ([]certificateBundle, error) {
+	namespaces := check.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	var bundles []certificateBundle
+	for _, namespace := range namespaces {
+		secrets, err := c.k8s.CoreV1().Secrets(namespace).List(metav1.ListOptions{
+			LabelSelector:  check.LabelSelector,
+			FieldSelector:  "type=" + string(v1.SecretTypeTLS),
+			TimeoutSeconds: timeoutSeconds(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets.Items {
+			bundles = append(bundles, certificateBundle{
+				Namespace:    secret.Namespace,
+				Secret:       secret.Name,
+				Certificates: parseCertificates(secret.Data[v1.TLSCertKey]),
+				Roots:        parseCertificates(secret.Data["ca.crt"]),
+			})
+		}
+	}
+	return bundles, nil
+}
+
+// verifyChain verifies the first certificate of the bundle using the remaining ones as intermediates,
+// trusting the system roots, the given roots and the roots of the bundle
+func verifyChain(bundle certificateBundle, roots []*x509.Certificate) error {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, root := range append(roots, bundle.Roots...) {
+		pool.AddCert(root)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range bundle.Certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = bundle.Certificates[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// parseCertificates returns every certificate of the PEM encoded data, skipping other blocks
+func parseCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			logger.Debugf("failed to parse certificate: %v", err)
+			continue
+		}
+		certs = append(certs, cert)
+	}
+}
@@ -1,15 +1,29 @@
 package checks
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/maintenance"
+	"github.com/robfig/cron/v3"
 )
 
 type Checker interface {
-	Run(config v1.CanarySpec) []*pkg.CheckResult
+	// Run runs every check of the checker type in config, cancelling them once ctx is done
+	Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult
 	Type() string
 }
 
+type timeoutCheck interface {
+	pkg.GenericCheck
+	pkg.WithTimeout
+}
+
 var All = []Checker{
 	&HelmChecker{},
 	&DNSChecker{},
@@ -21,6 +35,118 @@ var All = []Checker{
 	&DockerPushChecker{},
 	&PostgresChecker{},
 	&LdapChecker{},
+	&SSHChecker{},
+	&PrometheusChecker{},
 	NewPodChecker(),
 	NewNamespaceChecker(),
+	NewKubernetesChecker(),
+	NewJobChecker(),
+	NewConnectivityChecker(),
+	NewVolumeChecker(),
+	&SwiftChecker{},
+	&SwiftContainerChecker{},
+	&AzureBlobChecker{},
+	&AzureContainerChecker{},
+	&FilesystemChecker{},
+	NewCertificateChecker(),
+	&OIDCChecker{},
+}
+
+// Scheduled groups the checkers by the cron schedule their checks run on according to spec,
+// leaving out the checkers that are not scheduled
+func Scheduled(spec v1.CanarySpec) map[string][]Checker {
+	scheduled := make(map[string][]Checker)
+	for _, checker := range All {
+		if schedule := spec.GetSchedule(checker.Type()); schedule != "" {
+			scheduled[schedule] = append(scheduled[schedule], checker)
+		}
+	}
+	return scheduled
+}
+
+// Validate returns an error if the checks of spec cannot be scheduled, have an invalid maintenance window
+// or depend on themselves, canary is the namespace/name of the canary of spec or empty
+func Validate(canary string, spec v1.CanarySpec) error {
+	for schedule := range Scheduled(spec) {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return fmt.Errorf("invalid schedule %s: %v", schedule, err)
+		}
+	}
+	for _, window := range spec.Maintenance {
+		if _, err := maintenance.WindowActive(window, time.Now()); err != nil {
+			return fmt.Errorf("invalid maintenance window: %v", err)
+		}
+	}
+	for _, check := range pkg.AllChecks(spec) {
+		key := pkg.CheckKey("", check)
+		for _, window := range check.GetMaintenance() {
+			if _, err := maintenance.WindowActive(window, time.Now()); err != nil {
+				return fmt.Errorf("invalid maintenance window of %s: %v", key, err)
+			}
+		}
+	}
+	if cycles := dependencyCycles(canary, pkg.AllChecks(spec)); len(cycles) > 0 {
+		var keys []string
+		for key := range cycles {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("dependency cycle between %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// ScheduleInterval returns the time between the next two runs of a cron schedule
+func ScheduleInterval(schedule string) (time.Duration, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0, err
+	}
+	next := sched.Next(time.Now())
+	return sched.Next(next).Sub(next), nil
+}
+
+// checkTimeout returns the timeout of check, defaulting to the interval of the canary. A zero timeout
+// does not bound the check
+func checkTimeout(config v1.CanarySpec, check pkg.WithTimeout) time.Duration {
+	if timeout := check.GetTimeout(); timeout > 0 {
+		return time.Duration(timeout) * time.Second
+	}
+	return time.Duration(config.Interval) * time.Second
+}
+
+// deadlineTimeout returns the timeout of checks with a deadline in milliseconds, which default to the
+// longer of the interval and the deadline
+func deadlineTimeout(config v1.CanarySpec, check pkg.WithTimeout, deadline int64) time.Duration {
+	timeout := checkTimeout(config, check)
+	if check.GetTimeout() == 0 && timeout < time.Duration(deadline)*time.Millisecond {
+		timeout = time.Duration(deadline) * time.Millisecond
+	}
+	return timeout
+}
+
+// runWithTimeout runs fn with a context that is cancelled after timeout, returning a timed out result
+// for check if fn has not returned by then. fn is left running in the background and is expected to
+// return soon after its context is done, finished is called once it has returned
+func runWithTimeout(ctx context.Context, check pkg.GenericCheck, timeout time.Duration, fn func(ctx context.Context) *pkg.CheckResult, finished func()) *pkg.CheckResult {
+	if timeout <= 0 {
+		defer finished()
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	done := make(chan *pkg.CheckResult, 1)
+	go func() {
+		defer cancel()
+		defer finished()
+		done <- fn(ctx)
+	}()
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return timedOutf(check, timeout, "timed out after %s", timeout)
+		}
+		return Failf(check, "cancelled: %v", ctx.Err())
+	}
 }
@@ -0,0 +1,95 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+)
+
+// blockingChecker runs http checks with a timeout that block until their context is done, unless they
+// are named in passing
+type blockingChecker struct {
+	timeout   time.Duration
+	passing   map[string]bool
+	cancelled chan string
+}
+
+func (c *blockingChecker) Type() string {
+	return "http"
+}
+
+func (c *blockingChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.HTTP {
+		conf := conf
+		group.RunWithTimeout(conf, c.timeout, func(ctx context.Context) *pkg.CheckResult {
+			if c.passing[conf.Name] {
+				return Passf(conf, "passed")
+			}
+			<-ctx.Done()
+			c.cancelled <- conf.Name
+			return Failf(conf, "cancelled: %v", ctx.Err())
+		})
+	}
+	return group.Wait()
+}
+
+func TestRunChecksTimeout(t *testing.T) {
+	timeout := 100 * time.Millisecond
+	checker := &blockingChecker{timeout: timeout, passing: map[string]bool{"fast": true}, cancelled: make(chan string, 1)}
+	start := time.Now()
+	results := RunChecks(context.Background(), "", []Checker{checker}, v1.CanarySpec{HTTP: []v1.HTTPCheck{httpCheck("slow"), httpCheck("fast")}})
+	elapsed := time.Since(start)
+
+	if elapsed < timeout || elapsed > timeout+time.Second {
+		t.Errorf("Expected the checks to return after %s, but they took %s", timeout, elapsed)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, but found %d", len(results))
+	}
+	slow, fast := results[0], results[1]
+	if !slow.TimedOut || slow.Pass || slow.Invalid {
+		t.Errorf("Expected slow to time out, but found pass=%v timedOut=%v: %s", slow.Pass, slow.TimedOut, slow.Message)
+	}
+	if slow.Duration != timeout.Milliseconds() {
+		t.Errorf("Expected slow to take %dms, but found %dms", timeout.Milliseconds(), slow.Duration)
+	}
+	if !fast.Pass || fast.TimedOut {
+		t.Errorf("Expected fast to pass, but found pass=%v timedOut=%v: %s", fast.Pass, fast.TimedOut, fast.Message)
+	}
+
+	select {
+	case name := <-checker.cancelled:
+		if name != "slow" {
+			t.Errorf("Expected slow to be cancelled, but found %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the context of slow to be cancelled once it timed out")
+	}
+}
+
+func TestRunWithTimeoutCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	check := httpCheck("cancelled")
+	result := runWithTimeout(ctx, check, time.Minute, func(ctx context.Context) *pkg.CheckResult {
+		<-ctx.Done()
+		return Failf(check, "cancelled: %v", ctx.Err())
+	}, func() { close(finished) })
+
+	if result.TimedOut || result.Pass {
+		t.Errorf("Expected a cancelled check to fail without timing out, but found pass=%v timedOut=%v", result.Pass, result.TimedOut)
+	}
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Errorf("Expected finished to be called once the check returned")
+	}
+}
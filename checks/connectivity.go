@@ -0,0 +1,484 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	canaryv1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	perrors "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"golang.org/x/sync/semaphore"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	connectivityCheckSelector = "canary-checker.flanksource.com/connectivityCheck"
+
+	defaultConnectivityImage           = "busybox"
+	defaultConnectivityPort            = 8080
+	defaultConnectivityProbeTimeout    = 2000
+	defaultConnectivityScheduleTimeout = 60 * time.Second
+	defaultConnectivityDeadline        = 5 * time.Minute
+)
+
+type ConnectivityChecker struct {
+	lock   *semaphore.Weighted
+	k8s    *kubernetes.Clientset
+	config *rest.Config
+}
+
+// connectivityProbe is a probe pod scheduled on a node together with its service
+type connectivityProbe struct {
+	node      string
+	pod       *v1.Pod
+	service   *v1.Service
+	targets   map[string]string
+	failures  map[string][]string
+	succeeded int
+}
+
+func NewConnectivityChecker() *ConnectivityChecker {
+	cc := &ConnectivityChecker{
+		lock: semaphore.NewWeighted(1),
+	}
+
+	config, err := pkg.NewK8sRestConfig()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return cc
+	}
+	k8sClient, err := pkg.NewK8sClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return cc
+	}
+
+	cc.config = config
+	cc.k8s = k8sClient
+
+	return cc
+}
+
+// Type: returns checker type
+func (c *ConnectivityChecker) Type() string {
+	return "connectivity"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *ConnectivityChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newSerialCheckGroup(ctx, config)
+	for _, conf := range config.Connectivity {
+		conf := conf
+		group.RunWithTimeout(conf, deadlineTimeout(config, conf, conf.Deadline), func(ctx context.Context) *pkg.CheckResult {
+			// the deadline only starts once the check is running
+			deadline, _ := ctx.Deadline()
+			return c.Check(conf, deadline)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : Schedule a probe pod on every matching node and probe every pair of nodes
+// Returns check result and metrics
+func (c *ConnectivityChecker) Check(check canaryv1.ConnectivityCheck, checkDeadline time.Time) *pkg.CheckResult {
+	if !c.lock.TryAcquire(1) {
+		logger.Tracef("Check already in progress, skipping")
+		return nil
+	}
+	defer func() { c.lock.Release(1) }()
+
+	// checks without a deadline, timeout or interval are not bounded by their context
+	if checkDeadline.IsZero() {
+		checkDeadline = time.Now().Add(defaultConnectivityDeadline)
+	}
+
+	if err := c.Cleanup(check); err != nil {
+		return unexpectedErrorf(check, err, "failed to cleanup old artifacts")
+	}
+
+	startTimer := NewTimer()
+	logger.Debugf("Running connectivity check %s", check.Name)
+
+	nodes, err := c.k8s.CoreV1().Nodes().List(metav1.ListOptions{LabelSelector: labels.SelectorFromSet(check.NodeSelector).String()})
+	if err != nil {
+		return unexpectedErrorf(check, err, "cannot connect to API server")
+	}
+	var probes []*connectivityProbe
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		probes = append(probes, &connectivityProbe{node: node.Name})
+	}
+	sort.Slice(probes, func(i, j int) bool { return probes[i].node < probes[j].node })
+	if len(probes) < 2 {
+		return Failf(check, "found %d schedulable nodes, at least 2 are required", len(probes))
+	}
+
+	defer func() {
+		if err := c.Cleanup(check); err != nil {
+			logger.Errorf("failed to cleanup connectivity check %s: %v", check.Name, err)
+		}
+	}()
+
+	scheduleTimer := NewTimer()
+	for _, probe := range probes {
+		if err := c.createProbe(check, probe); err != nil {
+			return unexpectedErrorf(check, err, "failed to create probe on %s", probe.node)
+		}
+	}
+	scheduleTimeout := defaultConnectivityScheduleTimeout
+	if check.ScheduleTimeout > 0 {
+		scheduleTimeout = time.Duration(check.ScheduleTimeout) * time.Millisecond
+	}
+	for _, probe := range probes {
+		pod, err := c.waitForProbe(probe.pod, scheduleTimeout)
+		if err != nil {
+			return Failf(check, "probe on %s is not running: %v", probe.node, err)
+		}
+		probe.pod = pod
+	}
+	scheduleTime := scheduleTimer.Elapsed()
+
+	for _, target := range probes {
+		target.targets = map[string]string{"podIP": target.pod.Status.PodIP}
+		if !check.SkipService {
+			target.targets["clusterIP"] = target.service.Spec.ClusterIP
+		}
+		if !check.SkipDNS {
+			target.targets["dns"] = fmt.Sprintf("%s.%s.svc", target.service.Name, target.service.Namespace)
+		}
+	}
+
+	probeTimer := NewTimer()
+	for i, source := range probes {
+		if time.Now().After(checkDeadline) {
+			return Failf(check, "deadline exceeded after probing from %d/%d nodes", i, len(probes))
+		}
+		if err := c.probe(check, source, probes); err != nil {
+			return unexpectedErrorf(check, err, "failed to probe from %s", source.node)
+		}
+	}
+	probeTime := probeTimer.Elapsed()
+
+	var succeeded, failed int
+	for _, source := range probes {
+		succeeded += source.succeeded
+		for _, failures := range source.failures {
+			failed += len(failures)
+		}
+	}
+
+	deletion := NewTimer()
+	if err := c.Cleanup(check); err != nil {
+		return unexpectedErrorf(check, err, "failed to delete probes")
+	}
+
+	result := &pkg.CheckResult{
+		Check:    check,
+		Pass:     true,
+		Duration: int64(startTimer.Elapsed()),
+		Message:  fmt.Sprintf("%d probes between %d nodes", succeeded+failed, len(probes)),
+		Metrics: []pkg.Metric{
+			{
+				Name:   "schedule_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"connectivityCheck": check.Name},
+				Value:  scheduleTime,
+			},
+			{
+				Name:   "probe_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"connectivityCheck": check.Name},
+				Value:  probeTime,
+			},
+			{
+				Name:   "delete_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"connectivityCheck": check.Name},
+				Value:  deletion.Elapsed(),
+			},
+			{
+				Name:   "failed_probe_count",
+				Type:   metrics.GaugeType,
+				Labels: map[string]string{"connectivityCheck": check.Name},
+				Value:  float64(failed),
+			},
+		},
+	}
+	if failed > 0 {
+		return failWithMetrics(result, "%d/%d probes failed\n%s", failed, succeeded+failed, connectivityMatrix(probes))
+	}
+	return result
+}
+
+func (c *ConnectivityChecker) createProbe(check canaryv1.ConnectivityCheck, probe *connectivityProbe) error {
+	image := check.Image
+	if image == "" {
+		image = defaultConnectivityImage
+	}
+	port := check.Port
+	if port == 0 {
+		port = defaultConnectivityPort
+	}
+
+	name := probeName(check.Name, probe.node)
+	selectorLabels := map[string]string{
+		connectivityCheckSelector: c.connectivityCheckSelectorValue(check),
+		podGeneralSelector:        "true",
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: check.Namespace,
+			Labels: map[string]string{
+				podLabelSelector: name,
+			},
+		},
+		Spec: v1.PodSpec{
+			// the hostname label of a node does not always match its name
+			NodeName:          probe.node,
+			PriorityClassName: check.PriorityClass,
+			Containers: []v1.Container{
+				{
+					Name:    "probe",
+					Image:   image,
+					Command: []string{"sh", "-c", fmt.Sprintf("mkdir -p /tmp/www && echo ok > /tmp/www/index.html && exec httpd -f -p %d -h /tmp/www", port)},
+					Ports: []v1.ContainerPort{
+						{ContainerPort: int32(port), Protocol: v1.ProtocolTCP},
+					},
+				},
+			},
+			// tolerate every taint so that tainted nodes are probed as well
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+	for k, v := range selectorLabels {
+		pod.Labels[k] = v
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: check.Namespace,
+			Labels:    selectorLabels,
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{
+					Name:       "probe",
+					Protocol:   v1.ProtocolTCP,
+					Port:       int32(port),
+					TargetPort: intstr.FromInt(int(port)),
+				},
+			},
+			Selector: map[string]string{
+				podLabelSelector: name,
+			},
+		},
+	}
+
+	var err error
+	if probe.pod, err = c.k8s.CoreV1().Pods(check.Namespace).Create(pod); err != nil {
+		return perrors.Wrapf(err, "Failed to create pod %s in namespace %s", pod.Name, check.Namespace)
+	}
+	if probe.service, err = c.k8s.CoreV1().Services(check.Namespace).Create(svc); err != nil {
+		return perrors.Wrapf(err, "Failed to create service %s in namespace %s", svc.Name, check.Namespace)
+	}
+	return nil
+}
+
+// probeName returns the name of the probe pod and service of node, which must be a DNS label. Node names
+// containing dots are flattened and long names are truncated with a hash of the full name to stay unique
+func probeName(check, node string) string {
+	name := strings.ReplaceAll(fmt.Sprintf("%s-%s", check, node), ".", "-")
+	if len(name) <= 63 {
+		return strings.TrimRight(name, "-")
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	return fmt.Sprintf("%s-%08x", strings.TrimRight(name[:54], "-"), hash.Sum32())
+}
+
+// waitForProbe waits for a probe pod to be running and ready, or returns an
+// error if the timeout is exceeded
+func (c *ConnectivityChecker) waitForProbe(pod *v1.Pod, timeout time.Duration) (*v1.Pod, error) {
+	pods := c.k8s.CoreV1().Pods(pod.Namespace)
+	start := time.Now()
+	for {
+		p, err := pods.Get(pod.Name, metav1.GetOptions{})
+		if err == nil && p.Status.Phase == v1.PodRunning && p.Status.PodIP != "" {
+			return p, nil
+		}
+		if err == nil && p.Status.Phase == v1.PodFailed {
+			return p, fmt.Errorf("pod %s failed: %s", pod.Name, p.Status.Message)
+		}
+		if start.Add(timeout).Before(time.Now()) {
+			if err != nil {
+				return pod, err
+			}
+			return p, fmt.Errorf("Timeout exceeded waiting for %s, phase is %s", pod.Name, p.Status.Phase)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// probe runs a single script inside the source pod that probes every target of every other
+// probe pod, and records the failures by target node
+func (c *ConnectivityChecker) probe(check canaryv1.ConnectivityCheck, source *connectivityProbe, probes []*connectivityProbe) error {
+	port := check.Port
+	if port == 0 {
+		port = defaultConnectivityPort
+	}
+	timeout := check.ProbeTimeout
+	if timeout == 0 {
+		timeout = defaultConnectivityProbeTimeout
+	}
+	// wget only supports a timeout in whole seconds
+	timeoutSeconds := (timeout + 999) / 1000
+
+	var script strings.Builder
+	for _, target := range probes {
+		if target == source {
+			continue
+		}
+		for _, kind := range sortedKeys(target.targets) {
+			fmt.Fprintf(&script, "if wget -q -T %d -O /dev/null http://%s:%d/; then echo '%s %s ok'; else echo '%s %s failed'; fi\n",
+				timeoutSeconds, target.targets[kind], port, target.node, kind, target.node, kind)
+		}
+	}
+
+	stdout, stderr, err := c.exec(source.pod, "probe", "sh", "-c", script.String())
+	if err != nil {
+		return perrors.Wrapf(err, "failed to exec in %s: %s", source.pod.Name, stderr)
+	}
+
+	source.failures = make(map[string][]string)
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[2] == "ok" {
+			source.succeeded++
+		} else {
+			source.failures[fields[0]] = append(source.failures[fields[0]], fields[1])
+		}
+	}
+	return nil
+}
+
+func (c *ConnectivityChecker) exec(pod *v1.Pod, container string, command ...string) (string, string, error) {
+	if c.config == nil {
+		return "", "", fmt.Errorf("Connection to k8s not established")
+	}
+	req := c.k8s.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+	var stdout, stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	return stdout.String(), stderr.String(), err
+}
+
+func (c *ConnectivityChecker) Cleanup(check canaryv1.ConnectivityCheck) error {
+	if c.k8s == nil {
+		return fmt.Errorf("Connection to k8s not established")
+	}
+	listOptions := metav1.ListOptions{LabelSelector: c.connectivityCheckSelector(check)}
+
+	err := c.k8s.CoreV1().Pods(check.Namespace).DeleteCollection(nil, listOptions)
+	if err != nil && !errors.IsNotFound(err) {
+		return perrors.Wrapf(err, "Failed to delete pods for check %s in namespace %s : %v", check.Name, check.Namespace, err)
+	}
+
+	services, err := c.k8s.CoreV1().Services(check.Namespace).List(listOptions)
+	if err != nil {
+		return perrors.Wrapf(err, "Failed to get services for check %s in namespace %s : %v", check.Name, check.Namespace, err)
+	}
+	for _, s := range services.Items {
+		err = c.k8s.CoreV1().Services(check.Namespace).Delete(s.Name, nil)
+		if err != nil && !errors.IsNotFound(err) {
+			return perrors.Wrapf(err, "Failed to delete service %s in namespace %s : %v", s.Name, check.Namespace, err)
+		}
+	}
+	return nil
+}
+
+func (c *ConnectivityChecker) connectivityCheckSelectorValue(check canaryv1.ConnectivityCheck) string {
+	return fmt.Sprintf("%s.%s", check.Name, check.Namespace)
+}
+
+func (c *ConnectivityChecker) connectivityCheckSelector(check canaryv1.ConnectivityCheck) string {
+	return fmt.Sprintf("%s=%s", connectivityCheckSelector, c.connectivityCheckSelectorValue(check))
+}
+
+// connectivityMatrix renders a table with a row per source node and a column per
+// target node, listing the failed probe kinds of every pair
+func connectivityMatrix(probes []*connectivityProbe) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	header := []string{"from \\ to"}
+	for _, target := range probes {
+		header = append(header, target.node)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, source := range probes {
+		row := []string{source.node}
+		for _, target := range probes {
+			switch {
+			case source == target:
+				row = append(row, "-")
+			case len(source.failures[target.node]) > 0:
+				row = append(row, strings.Join(source.failures[target.node], ","))
+			default:
+				row = append(row, "ok")
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,274 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/health"
+	"github.com/flanksource/commons/logger"
+)
+
+// dependencies tracks the checks of a run so that checks only run once the checks they depend on have
+// completed, and are blocked if any of them failed. Dependencies outside of the run, such as checks of
+// other canaries or schedules, are looked up in their latest reported state
+type dependencies struct {
+	canary string
+	mtx    sync.Mutex
+	checks map[string]*dependency
+	// pending is the number of checkers that have not submitted all their checks yet
+	pending   int
+	submitted chan struct{}
+	cycles    map[string]bool
+}
+
+// dependency is a check of the run, checks of the same key complete once all of them completed
+type dependency struct {
+	refs      []string
+	remaining int
+	failed    *pkg.CheckResult
+	done      chan struct{}
+	closed    bool
+}
+
+func newDependencies(canary string, checkers int) *dependencies {
+	d := &dependencies{
+		canary:    canary,
+		checks:    make(map[string]*dependency),
+		pending:   checkers,
+		submitted: make(chan struct{}),
+	}
+	if checkers == 0 {
+		close(d.submitted)
+	}
+	return d
+}
+
+func (d *dependencies) register(check pkg.GenericCheck) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	key := pkg.CheckKey(d.canary, check)
+	dep, found := d.checks[key]
+	if !found {
+		dep = &dependency{done: make(chan struct{})}
+		d.checks[key] = dep
+	}
+	dep.refs = append(dep.refs, check.GetDependsOn()...)
+	dep.remaining++
+}
+
+// complete records the result of check, a nil result is a check that was skipped
+func (d *dependencies) complete(check pkg.GenericCheck, result *pkg.CheckResult) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	dep := d.checks[pkg.CheckKey(d.canary, check)]
+	if result != nil && !result.Pass && dep.failed == nil {
+		dep.failed = result
+	}
+	dep.remaining--
+	d.closeCompleted()
+}
+
+// submit records that a checker has submitted all its checks, once all checkers have submitted their
+// checks the dependencies of the run are known
+func (d *dependencies) submit() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.pending--
+	if d.pending > 0 {
+		return
+	}
+	d.cycles = d.findCycles()
+	close(d.submitted)
+	d.closeCompleted()
+}
+
+// closeCompleted marks the checks that have completed as done, which is only known once no more
+// checks of the same key can be submitted
+func (d *dependencies) closeCompleted() {
+	if d.pending > 0 {
+		return
+	}
+	for _, dep := range d.checks {
+		if dep.remaining == 0 && !dep.closed {
+			dep.closed = true
+			close(dep.done)
+		}
+	}
+}
+
+// resolve returns the keys the check referenced by ref may have, relative to the canary or absolute
+func (d *dependencies) resolve(ref string) []string {
+	if d.canary == "" {
+		return []string{ref}
+	}
+	return []string{d.canary + "/" + ref, ref}
+}
+
+// findCycles returns the keys of the checks of the run that depend on themselves
+func (d *dependencies) findCycles() map[string]bool {
+	graph := make(map[string][]string)
+	for key := range d.checks {
+		graph[key] = nil
+	}
+	for key, dep := range d.checks {
+		for _, ref := range dep.refs {
+			for _, candidate := range d.resolve(ref) {
+				if _, found := d.checks[candidate]; found {
+					graph[key] = append(graph[key], candidate)
+					break
+				}
+			}
+		}
+	}
+
+	cycles := make(map[string]bool)
+	visited := make(map[string]bool)
+	var stack []string
+	onStack := make(map[string]bool)
+	var visit func(key string)
+	visit = func(key string) {
+		visited[key] = true
+		stack = append(stack, key)
+		onStack[key] = true
+		for _, next := range graph[key] {
+			if onStack[next] {
+				for i := len(stack) - 1; i >= 0; i-- {
+					cycles[stack[i]] = true
+					if stack[i] == next {
+						break
+					}
+				}
+			} else if !visited[next] {
+				visit(next)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		onStack[key] = false
+	}
+	for key := range graph {
+		if !visited[key] {
+			visit(key)
+		}
+	}
+	return cycles
+}
+
+// dependencyCycles returns the keys of checks that depend on themselves, canary is the namespace/name of
+// the canary the checks belong to or empty
+func dependencyCycles(canary string, checks []pkg.GenericCheck) map[string]bool {
+	d := newDependencies(canary, 0)
+	for _, check := range checks {
+		d.register(check)
+	}
+	return d.findCycles()
+}
+
+// wait waits for the dependencies of check to complete, returning a result if the check should not run
+func (d *dependencies) wait(ctx context.Context, check pkg.GenericCheck) *pkg.CheckResult {
+	refs := check.GetDependsOn()
+	if len(refs) == 0 {
+		return nil
+	}
+	select {
+	case <-d.submitted:
+	case <-ctx.Done():
+		return Failf(check, "cancelled: %v", ctx.Err())
+	}
+	key := pkg.CheckKey(d.canary, check)
+	if d.cycles[key] {
+		return invalidErrorf(check, fmt.Errorf("%s", strings.Join(refs, ", ")), "dependency cycle")
+	}
+
+	for _, ref := range refs {
+		depKey, dep := d.lookup(ref)
+		if dep != nil {
+			select {
+			case <-dep.done:
+			case <-ctx.Done():
+				return Failf(check, "cancelled: %v", ctx.Err())
+			}
+			if failed := dep.failed; failed != nil {
+				if failed.BlockedBy != "" {
+					return blockedf(check, failed.BlockedBy, "%s", failed.Message)
+				}
+				return blockedf(check, depKey, "blocked by %s: %s", depKey, failed.Message)
+			}
+			continue
+		}
+		found := false
+		for _, candidate := range d.resolve(ref) {
+			pass, rootCause, message, ok := health.Status(candidate)
+			if !ok {
+				continue
+			}
+			found = true
+			if pass {
+				break
+			}
+			// the message of a blocked dependency already names the root cause
+			if rootCause != candidate {
+				return blockedf(check, rootCause, "%s", message)
+			}
+			return blockedf(check, rootCause, "blocked by %s: %s", rootCause, message)
+		}
+		if !found {
+			logger.Debugf("[%s] %s depends on %s which has not reported yet", check.GetType(), pkg.CheckName(check), ref)
+		}
+	}
+	return nil
+}
+
+// lookup returns the check of the run referenced by ref, if any
+func (d *dependencies) lookup(ref string) (string, *dependency) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for _, candidate := range d.resolve(ref) {
+		if dep, found := d.checks[candidate]; found {
+			return candidate, dep
+		}
+	}
+	return "", nil
+}
+
+type checkerRunKey struct{}
+
+// checkerRun is the run of a checker as part of RunChecks
+type checkerRun struct {
+	deps *dependencies
+	once sync.Once
+}
+
+func checkerRunFrom(ctx context.Context) *checkerRun {
+	run, _ := ctx.Value(checkerRunKey{}).(*checkerRun)
+	return run
+}
+
+// submitted records that the checker has submitted all its checks
+func (r *checkerRun) submitted() {
+	if r == nil {
+		return
+	}
+	r.once.Do(r.deps.submit)
+}
+
+func (r *checkerRun) register(check pkg.GenericCheck) {
+	if r != nil {
+		r.deps.register(check)
+	}
+}
+
+func (r *checkerRun) wait(ctx context.Context, check pkg.GenericCheck) *pkg.CheckResult {
+	if r == nil {
+		return nil
+	}
+	return r.deps.wait(ctx, check)
+}
+
+func (r *checkerRun) complete(check pkg.GenericCheck, result *pkg.CheckResult) {
+	if r != nil {
+		r.deps.complete(check, result)
+	}
+}
@@ -0,0 +1,194 @@
+package checks
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/health"
+)
+
+// fakeChecker runs the http checks of a config, failing the checks named in failing
+type fakeChecker struct {
+	failing map[string]bool
+	mtx     sync.Mutex
+	ran     []string
+}
+
+func (c *fakeChecker) Type() string {
+	return "http"
+}
+
+func (c *fakeChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.HTTP {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			c.mtx.Lock()
+			c.ran = append(c.ran, conf.Name)
+			c.mtx.Unlock()
+			if c.failing[conf.Name] {
+				return Failf(conf, "failed")
+			}
+			return Passf(conf, "passed")
+		})
+	}
+	return group.Wait()
+}
+
+func httpCheck(name string, dependsOn ...string) v1.HTTPCheck {
+	return v1.HTTPCheck{Name: name, Endpoint: "http://" + name, DependsOn: dependsOn}
+}
+
+// state returns pass, fail, invalid or the root cause of a blocked result
+func state(result *pkg.CheckResult) string {
+	switch {
+	case result.BlockedBy != "":
+		return "blocked by " + result.BlockedBy
+	case result.Invalid:
+		return "invalid"
+	case result.Pass:
+		return "pass"
+	}
+	return "fail"
+}
+
+func TestRunChecksDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		canary  string
+		checks  []v1.HTTPCheck
+		failing []string
+		// reported is the state of checks of other runs
+		reported map[string]bool
+		want     map[string]string
+		// ran lists the checks in the order they must run in, checks that are not listed must not run
+		ran []string
+	}{
+		{
+			name:   "independent checks",
+			checks: []v1.HTTPCheck{httpCheck("a"), httpCheck("b")},
+			want:   map[string]string{"a": "pass", "b": "pass"},
+		},
+		{
+			name:   "dependency passes",
+			checks: []v1.HTTPCheck{httpCheck("b", "http/a"), httpCheck("a")},
+			want:   map[string]string{"a": "pass", "b": "pass"},
+			ran:    []string{"a", "b"},
+		},
+		{
+			name:    "dependency fails",
+			checks:  []v1.HTTPCheck{httpCheck("a"), httpCheck("b", "http/a")},
+			failing: []string{"a"},
+			want:    map[string]string{"a": "fail", "b": "blocked by http/a"},
+			ran:     []string{"a"},
+		},
+		{
+			name:    "transitive dependency fails",
+			checks:  []v1.HTTPCheck{httpCheck("c", "http/b"), httpCheck("b", "http/a"), httpCheck("a")},
+			failing: []string{"a"},
+			want:    map[string]string{"a": "fail", "b": "blocked by http/a", "c": "blocked by http/a"},
+			ran:     []string{"a"},
+		},
+		{
+			name:   "cycle",
+			checks: []v1.HTTPCheck{httpCheck("a", "http/b"), httpCheck("b", "http/a"), httpCheck("c", "http/a")},
+			want:   map[string]string{"a": "invalid", "b": "invalid", "c": "blocked by http/a"},
+		},
+		{
+			name:   "self dependency",
+			checks: []v1.HTTPCheck{httpCheck("a", "http/a")},
+			want:   map[string]string{"a": "invalid"},
+		},
+		{
+			name:   "unknown dependency",
+			checks: []v1.HTTPCheck{httpCheck("a", "http/missing")},
+			want:   map[string]string{"a": "pass"},
+			ran:    []string{"a"},
+		},
+		{
+			name:     "reported dependency fails",
+			checks:   []v1.HTTPCheck{httpCheck("a", "http/other")},
+			reported: map[string]bool{"http/other": false},
+			want:     map[string]string{"a": "blocked by http/other"},
+		},
+		{
+			name:     "reported dependency passes",
+			checks:   []v1.HTTPCheck{httpCheck("a", "http/other")},
+			reported: map[string]bool{"http/other": true},
+			want:     map[string]string{"a": "pass"},
+			ran:      []string{"a"},
+		},
+		{
+			name:    "dependency within the canary",
+			canary:  "default/canary",
+			checks:  []v1.HTTPCheck{httpCheck("a"), httpCheck("b", "http/a")},
+			failing: []string{"a"},
+			want:    map[string]string{"a": "fail", "b": "blocked by default/canary/http/a"},
+			ran:     []string{"a"},
+		},
+	}
+	for _, tc := range tests {
+		for key, pass := range tc.reported {
+			health.Update(key, v1.CanarySpec{}, &pkg.CheckResult{Pass: pass})
+		}
+		checker := &fakeChecker{failing: make(map[string]bool)}
+		for _, name := range tc.failing {
+			checker.failing[name] = true
+		}
+		results := RunChecks(context.Background(), tc.canary, []Checker{checker}, v1.CanarySpec{HTTP: tc.checks})
+		for key := range tc.reported {
+			health.Remove(key)
+		}
+
+		if len(results) != len(tc.want) {
+			t.Errorf("Test %s failed. Expected %d results, but found %d", tc.name, len(tc.want), len(results))
+			continue
+		}
+		for _, result := range results {
+			name := result.Check.GetName()
+			if got := state(result); got != tc.want[name] {
+				t.Errorf("Test %s failed. Expected %s to %s, but found %s: %s", tc.name, name, tc.want[name], got, result.Message)
+			}
+		}
+		if tc.ran == nil {
+			continue
+		}
+		if len(checker.ran) != len(tc.ran) {
+			t.Errorf("Test %s failed. Expected %v to run, but found %v", tc.name, tc.ran, checker.ran)
+			continue
+		}
+		for i := range tc.ran {
+			if checker.ran[i] != tc.ran[i] {
+				t.Errorf("Test %s failed. Expected %v to run, but found %v", tc.name, tc.ran, checker.ran)
+				break
+			}
+		}
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name   string
+		canary string
+		checks []v1.HTTPCheck
+		valid  bool
+	}{
+		{name: "no dependencies", checks: []v1.HTTPCheck{httpCheck("a"), httpCheck("b")}, valid: true},
+		{name: "dependency", checks: []v1.HTTPCheck{httpCheck("a"), httpCheck("b", "http/a")}, valid: true},
+		{name: "unknown dependency", checks: []v1.HTTPCheck{httpCheck("a", "http/missing")}, valid: true},
+		{name: "self dependency", checks: []v1.HTTPCheck{httpCheck("a", "http/a")}, valid: false},
+		{name: "cycle", checks: []v1.HTTPCheck{httpCheck("a", "http/b"), httpCheck("b", "http/a")}, valid: false},
+		{name: "transitive cycle", checks: []v1.HTTPCheck{httpCheck("a", "http/c"), httpCheck("b", "http/a"), httpCheck("c", "http/b")}, valid: false},
+		{name: "absolute self dependency", canary: "default/canary", checks: []v1.HTTPCheck{httpCheck("a", "default/canary/http/a")}, valid: false},
+		{name: "dependency on another canary", canary: "default/canary", checks: []v1.HTTPCheck{httpCheck("a", "default/other/http/a")}, valid: true},
+	}
+	for _, tc := range tests {
+		err := Validate(tc.canary, v1.CanarySpec{Interval: 30, HTTP: tc.checks})
+		if valid := err == nil; valid != tc.valid {
+			t.Errorf("Test %s failed. Expected valid %v, but found %v: %v", tc.name, tc.valid, valid, err)
+		}
+	}
+}
@@ -20,17 +20,19 @@ func (c *DNSChecker) Type() string {
 	return "dns"
 }
 
-func (c *DNSChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *DNSChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.DNS {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
-func (c *DNSChecker) Check(check v1.DNSCheck) *pkg.CheckResult {
+func (c *DNSChecker) Check(ctx context.Context, check v1.DNSCheck) *pkg.CheckResult {
 	start := time.Now()
-	ctx := context.Background()
 	dialer, err := getDialer(check, check.Timeout)
 	if err != nil {
 		return Failf(check, "Failed to get dialer, %v", err)
@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -15,10 +17,13 @@ import (
 
 	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
 )
 
 var (
-	dockerClient *client.Client
+	dockerClient     *client.Client
+	dockerClientErr  error
+	dockerClientOnce sync.Once
 
 	size = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -39,22 +44,29 @@ var (
 )
 
 func init() {
-	var err error
-	dockerClient, err = client.NewEnvClient()
-	if err != nil {
-		panic(err)
-	}
 	prometheus.MustRegister(size, imagePullTime)
 }
 
+// getDockerClient lazily connects to the docker daemon, so that checks not
+// using the daemon keep working where it is unavailable
+func getDockerClient() (*client.Client, error) {
+	dockerClientOnce.Do(func() {
+		dockerClient, dockerClientErr = client.NewEnvClient()
+	})
+	return dockerClient, dockerClientErr
+}
+
 type DockerPullChecker struct{}
 
-func (c *DockerPullChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *DockerPullChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.DockerPull {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // Type: returns checker type
@@ -79,9 +91,20 @@ func getDigestFromOutput(out io.ReadCloser) string {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *DockerPullChecker) Check(check v1.DockerPullCheck) *pkg.CheckResult {
+func (c *DockerPullChecker) Check(ctx context.Context, check v1.DockerPullCheck) *pkg.CheckResult {
+	switch check.Mode {
+	case "", "daemon":
+	case "registry":
+		return c.checkRegistry(ctx, check)
+	default:
+		return invalidErrorf(check, fmt.Errorf("unknown mode %s", check.Mode), "mode must be either daemon or registry")
+	}
+
+	dockerClient, err := getDockerClient()
+	if err != nil {
+		return unexpectedErrorf(check, err, "cannot connect to the docker daemon")
+	}
 	start := time.Now()
-	ctx := context.Background()
 	authConfig := types.AuthConfig{
 		Username: check.Username,
 		Password: check.Password,
@@ -110,3 +133,94 @@ func (c *DockerPullChecker) Check(check v1.DockerPullCheck) *pkg.CheckResult {
 		Duration: elapsed.Milliseconds(),
 	}
 }
+
+// checkRegistry resolves the image to a manifest digest using the registry API,
+// and optionally downloads and verifies every blob of the manifest
+func (c *DockerPullChecker) checkRegistry(ctx context.Context, check v1.DockerPullCheck) *pkg.CheckResult {
+	ref, err := parseImageReference(check.Image)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid image")
+	}
+	registry := newRegistryClient(ctx, ref, check.Username, check.Password, check.SkipTLSVerify, check.PlainHTTP)
+
+	timer := NewTimer()
+	m, digest, err := registry.Manifest(ref.Reference)
+	if err != nil {
+		return Failf(check, "%v", err)
+	}
+	if m.isIndex() {
+		platformDigest := selectPlatform(m.Manifests)
+		if platformDigest == "" {
+			return Failf(check, "no manifest found for linux/amd64 in %s", digest)
+		}
+		if m, _, err = registry.Manifest(platformDigest); err != nil {
+			return Failf(check, "%v", err)
+		}
+	}
+	manifestTime := timer.Elapsed()
+
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+		Metrics: []pkg.Metric{
+			{
+				Name:   "manifest_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"image": check.Image},
+				Value:  manifestTime,
+			},
+		},
+	}
+
+	if check.ExpectedDigest != "" && digest != check.ExpectedDigest {
+		result.Duration = int64(timer.Elapsed())
+		return failWithMetrics(result, "digests do not match %s != %s", digest, check.ExpectedDigest)
+	}
+
+	var imageSize int64
+	for _, layer := range m.Layers {
+		imageSize += layer.Size
+	}
+	if check.ExpectedSize > 0 && imageSize != check.ExpectedSize {
+		result.Duration = int64(timer.Elapsed())
+		return failWithMetrics(result, "size does not match: %d != %d", imageSize, check.ExpectedSize)
+	}
+
+	if check.VerifyBlobs {
+		blobs := m.Layers
+		if m.Config != nil {
+			blobs = append([]descriptor{*m.Config}, blobs...)
+		}
+		blobTimer := NewTimer()
+		for _, blob := range blobs {
+			n, err := registry.Blob(blob.Digest)
+			if err != nil {
+				result.Duration = int64(timer.Elapsed())
+				return failWithMetrics(result, "%v", err)
+			}
+			if n != blob.Size {
+				result.Duration = int64(timer.Elapsed())
+				return failWithMetrics(result, "blob %s size does not match: %d != %d", blob.Digest, n, blob.Size)
+			}
+		}
+		result.Metrics = append(result.Metrics, pkg.Metric{
+			Name:   "blob_time",
+			Type:   metrics.HistogramType,
+			Labels: map[string]string{"image": check.Image},
+			Value:  blobTimer.Elapsed(),
+		})
+	}
+	result.Duration = int64(timer.Elapsed())
+	result.Message = digest
+	return result
+}
+
+// selectPlatform returns the digest of the linux/amd64 manifest of an index
+func selectPlatform(manifests []descriptor) string {
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+			return m.Digest
+		}
+	}
+	return ""
+}
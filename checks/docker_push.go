@@ -1,26 +1,41 @@
 package checks
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+)
+
+const (
+	mediaTypeDockerConfig = "application/vnd.docker.container.image.v1+json"
+	mediaTypeDockerLayer  = "application/vnd.docker.image.rootfs.diff.tar.gzip"
 )
 
 type DockerPushChecker struct{}
 
-func (c *DockerPushChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *DockerPushChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.DockerPush {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // Type: returns checker type
@@ -30,9 +45,20 @@ func (c *DockerPushChecker) Type() string {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *DockerPushChecker) Check(check v1.DockerPushCheck) *pkg.CheckResult {
+func (c *DockerPushChecker) Check(ctx context.Context, check v1.DockerPushCheck) *pkg.CheckResult {
+	switch check.Mode {
+	case "", "daemon":
+	case "registry":
+		return c.checkRegistry(ctx, check)
+	default:
+		return invalidErrorf(check, fmt.Errorf("unknown mode %s", check.Mode), "mode must be either daemon or registry")
+	}
+
+	dockerClient, err := getDockerClient()
+	if err != nil {
+		return unexpectedErrorf(check, err, "cannot connect to the docker daemon")
+	}
 	start := time.Now()
-	ctx := context.Background()
 	authConfig := types.AuthConfig{
 		Username: check.Username,
 		Password: check.Password,
@@ -66,3 +92,144 @@ func (c *DockerPushChecker) Check(check v1.DockerPushCheck) *pkg.CheckResult {
 		Metrics:  []pkg.Metric{},
 	}
 }
+
+// checkRegistry pushes a generated image with a unique layer under a timestamped tag,
+// pulls it back to verify its digest and deletes it again
+func (c *DockerPushChecker) checkRegistry(ctx context.Context, check v1.DockerPushCheck) *pkg.CheckResult {
+	ref, err := parseImageReference(check.Image)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid image")
+	}
+	ref.Reference = fmt.Sprintf("canary-%d", time.Now().Unix())
+	registry := newRegistryClient(ctx, ref, check.Username, check.Password, check.SkipTLSVerify, check.PlainHTTP)
+
+	layer, diffID, err := syntheticLayer()
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to generate layer")
+	}
+	config, err := json.Marshal(map[string]interface{}{
+		"architecture": "amd64",
+		"os":           "linux",
+		"config":       map[string]interface{}{},
+		"rootfs": map[string]interface{}{
+			"type":     "layers",
+			"diff_ids": []string{diffID},
+		},
+	})
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to generate config")
+	}
+
+	timer := NewTimer()
+	pushTimer := NewTimer()
+	layerDigest, err := registry.UploadBlob(layer)
+	if err != nil {
+		return Failf(check, "%v", err)
+	}
+	configDigest, err := registry.UploadBlob(config)
+	if err != nil {
+		return Failf(check, "%v", err)
+	}
+	body, err := json.Marshal(manifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeDockerManifest,
+		Config:        &descriptor{MediaType: mediaTypeDockerConfig, Size: int64(len(config)), Digest: configDigest},
+		Layers:        []descriptor{{MediaType: mediaTypeDockerLayer, Size: int64(len(layer)), Digest: layerDigest}},
+	})
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to generate manifest")
+	}
+	digest, err := registry.PutManifest(ref.Reference, mediaTypeDockerManifest, body)
+	if err != nil {
+		return Failf(check, "%v", err)
+	}
+	pushTime := pushTimer.Elapsed()
+
+	pullTimer := NewTimer()
+	pulled, pulledDigest, err := registry.Manifest(ref.Reference)
+	if err != nil {
+		c.deleteTag(registry, digest)
+		return Failf(check, "failed to pull %s: %v", ref, err)
+	}
+	if pulledDigest != digest || len(pulled.Layers) != 1 || pulled.Layers[0].Digest != layerDigest {
+		c.deleteTag(registry, digest)
+		return Failf(check, "pulled %s with digest %s, expected %s", ref, pulledDigest, digest)
+	}
+	if _, err := registry.Blob(layerDigest); err != nil {
+		c.deleteTag(registry, digest)
+		return Failf(check, "%v", err)
+	}
+	pullTime := pullTimer.Elapsed()
+
+	deleteTimer := NewTimer()
+	if err := registry.DeleteManifest(digest); err != nil {
+		return Failf(check, "failed to delete %s: %v", ref, err)
+	}
+	deleteTime := deleteTimer.Elapsed()
+
+	return &pkg.CheckResult{
+		Check:    check,
+		Pass:     true,
+		Duration: int64(timer.Elapsed()),
+		Message:  ref.String(),
+		Metrics: []pkg.Metric{
+			{
+				Name:   "push_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"image": check.Image},
+				Value:  pushTime,
+			},
+			{
+				Name:   "pull_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"image": check.Image},
+				Value:  pullTime,
+			},
+			{
+				Name:   "delete_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"image": check.Image},
+				Value:  deleteTime,
+			},
+		},
+	}
+}
+
+// deleteTag removes the pushed image after a failure, as a best effort
+func (c *DockerPushChecker) deleteTag(registry *registryClient, digest string) {
+	if err := registry.DeleteManifest(digest); err != nil {
+		logger.Errorf("failed to delete %s: %v", registry.ref, err)
+	}
+}
+
+// syntheticLayer generates a gzipped layer containing a single file with random content,
+// so that every push uploads a new blob. It returns the layer and its uncompressed digest.
+func syntheticLayer() ([]byte, string, error) {
+	content := make([]byte, 16)
+	if _, err := rand.Read(content); err != nil {
+		return nil, "", err
+	}
+	data := []byte(fmt.Sprintf("%s %s\n", time.Now().UTC().Format(time.RFC3339Nano), hex.EncodeToString(content)))
+
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	if err := tw.WriteHeader(&tar.Header{Name: "canary", Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return nil, "", err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, "", err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	var layer bytes.Buffer
+	gw := gzip.NewWriter(&layer)
+	if _, err := gw.Write(tarball.Bytes()); err != nil {
+		return nil, "", err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, "", err
+	}
+	return layer.Bytes(), sha256Digest(tarball.Bytes()), nil
+}
@@ -0,0 +1,189 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+)
+
+type FilesystemChecker struct{}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *FilesystemChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.Filesystem {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Type: returns checker type
+func (c *FilesystemChecker) Type() string {
+	return "filesystem"
+}
+
+// Check : Check the files matching the path for count, freshness, size and content
+// Returns check result and metrics
+func (c *FilesystemChecker) Check(ctx context.Context, check v1.FilesystemCheck) *pkg.CheckResult {
+	var contentRegex *regexp.Regexp
+	if check.ContentRegex != "" {
+		re, err := regexp.Compile(check.ContentRegex)
+		if err != nil {
+			return invalidErrorf(check, err, "failed to compile regex: %s", check.ContentRegex)
+		}
+		contentRegex = re
+	}
+
+	files, err := listFiles(ctx, check.Path, check.Recursive)
+	if err != nil {
+		return Failf(check, "Failed to list %s: %v", check.Path, err)
+	}
+
+	var latestFile string
+	var latestInfo os.FileInfo
+	var totalSize int64
+	for path, info := range files {
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latestFile = path
+			latestInfo = info
+		}
+		totalSize += info.Size()
+	}
+
+	labels := map[string]string{"path": check.Path}
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+		Metrics: []pkg.Metric{
+			{Name: "file_count", Type: metrics.GaugeType, Labels: labels, Value: float64(len(files))},
+			{Name: "total_size", Type: metrics.GaugeType, Labels: labels, Value: float64(totalSize)},
+		},
+	}
+	if check.MinCount > 0 && int64(len(files)) < check.MinCount {
+		return failWithMetrics(result, "Found %d files required at least %d files", len(files), check.MinCount)
+	}
+	if check.MaxCount > 0 && int64(len(files)) > check.MaxCount {
+		return failWithMetrics(result, "Found %d files required at most %d files", len(files), check.MaxCount)
+	}
+	if latestInfo == nil {
+		return failWithMetrics(result, "could not find any matching files")
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "last_write", Type: metrics.GaugeType, Labels: labels, Value: float64(latestInfo.ModTime().Unix())})
+
+	latestFileAge := time.Since(latestInfo.ModTime())
+	if check.MaxAge > 0 && latestFileAge.Seconds() > float64(check.MaxAge) {
+		return failWithMetrics(result, "Latest file %s age is %f seconds required at most %d seconds", latestFile, latestFileAge.Seconds(), check.MaxAge)
+	}
+	if check.MinSize > 0 && latestInfo.Size() < check.MinSize {
+		return failWithMetrics(result, "Latest file %s is %d bytes required at least %d bytes", latestFile, latestInfo.Size(), check.MinSize)
+	}
+	if check.SHA256 != "" {
+		checksum, err := fileChecksum(ctx, latestFile)
+		if err != nil {
+			return failWithMetrics(result, "Failed to read %s: %v", latestFile, err)
+		}
+		if !strings.EqualFold(checksum, check.SHA256) {
+			return failWithMetrics(result, "Latest file %s has sha256 %s expected %s", latestFile, checksum, check.SHA256)
+		}
+	}
+	if contentRegex != nil {
+		f, err := os.Open(latestFile)
+		if err != nil {
+			return failWithMetrics(result, "Failed to read %s: %v", latestFile, err)
+		}
+		matched := contentRegex.MatchReader(bufio.NewReader(contextReader{ctx: ctx, r: f}))
+		f.Close()
+		if err := ctx.Err(); err != nil {
+			return failWithMetrics(result, "Failed to read %s: %v", latestFile, err)
+		}
+		if !matched {
+			return failWithMetrics(result, "Latest file %s does not match %s", latestFile, check.ContentRegex)
+		}
+	}
+
+	result.Message = fmt.Sprintf("maxAge=%s size=%s files=%d totalSize=%s", age(latestFileAge), mb(latestInfo.Size()), len(files), mb(totalSize))
+	return result
+}
+
+// listFiles returns the regular files matching the glob pattern, expanding directories to
+// the files they contain
+func listFiles(ctx context.Context, pattern string, recursive bool) (map[string]os.FileInfo, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]os.FileInfo)
+	for _, match := range matches {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if info.Mode().IsRegular() {
+				files[match] = info
+			}
+			continue
+		}
+		if recursive {
+			err := filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if info.Mode().IsRegular() {
+					files[path] = info
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries, err := ioutil.ReadDir(match)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Mode().IsRegular() {
+				files[filepath.Join(match, entry.Name())] = entry
+			}
+		}
+	}
+	return files, nil
+}
+
+func fileChecksum(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, contextReader{ctx: ctx, r: f}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -1,23 +1,41 @@
 package checks
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	pusher "github.com/chartmuseum/helm-push/pkg/chartmuseum"
 	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
 	"github.com/flanksource/commons/logger"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
-	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+const (
+	testChartName = "test-chart"
+
+	mediaTypeHelmConfig = "application/vnd.cncf.helm.config.v1+json"
+	mediaTypeHelmChart  = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
 )
 
 type HelmChecker struct{}
@@ -29,27 +47,62 @@ func (c *HelmChecker) Type() string {
 	return "helm"
 }
 
-func (c *HelmChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *HelmChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.Helm {
-		results = append(results, c.Check(conf))
+		conf := conf
+		timeout := checkTimeout(config, conf)
+		if conf.Mode == "install" {
+			// installs run for as long as the helm actions may take, unless the check has a timeout
+			timeout = deadlineTimeout(config, conf, installDeadline(conf).Milliseconds())
+		}
+		group.RunWithTimeout(conf, timeout, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
-func (c *HelmChecker) Check(config v1.HelmCheck) *pkg.CheckResult {
+func (c *HelmChecker) Check(ctx context.Context, config v1.HelmCheck) *pkg.CheckResult {
+	switch config.Mode {
+	case "", "chartmuseum":
+		return c.checkChartmuseum(ctx, config)
+	case "repository":
+		return c.checkRepository(ctx, config)
+	case "oci":
+		return c.checkOCI(ctx, config)
+	case "install":
+		return c.checkInstall(ctx, config)
+	}
+	return invalidErrorf(config, fmt.Errorf("unknown mode %s", config.Mode), "mode must be one of chartmuseum, repository, oci or install")
+}
+
+func (c *HelmChecker) checkChartmuseum(ctx context.Context, config v1.HelmCheck) *pkg.CheckResult {
 	start := time.Now()
 	var uploadOK, downloadOK bool = true, true
 	chartmuseum := fmt.Sprintf("%s/chartrepo/%s/", config.Chartmuseum, config.Project)
+	var caFile string
+	if config.CaFile != nil {
+		caFile = *config.CaFile
+	}
+
+	// every check works in its own directory, so that concurrent checks don't collide
+	dir, err := ioutil.TempDir("", "canary_checker_helm")
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to create temp directory")
+	}
+	defer os.RemoveAll(dir)
+
 	logger.Tracef("Uploading test chart")
 	client, _ := pusher.NewClient(
 		pusher.URL(chartmuseum),
 		pusher.Username(config.Username),
 		pusher.Password(config.Password),
 		pusher.ContextPath(""),
-		pusher.Timeout(60),
-		pusher.CAFile(*config.CaFile))
-	chartPath, err := createTestChart()
+		// the push client does not accept a context, so it times out with the check instead
+		pusher.Timeout(int64(math.Ceil(capTimeout(ctx, 60*time.Second).Seconds()))),
+		pusher.CAFile(caFile))
+	chartPath, err := createTestChart(dir, "")
 	if err != nil {
 		return &pkg.CheckResult{
 			Pass:     false,
@@ -59,7 +112,7 @@ func (c *HelmChecker) Check(config v1.HelmCheck) *pkg.CheckResult {
 			Message:  fmt.Sprintf("Failed to create test chart: %v", err),
 		}
 	}
-	response, err := client.UploadChartPackage(*chartPath, false)
+	response, err := client.UploadChartPackage(chartPath, false)
 
 	if err != nil {
 		return &pkg.CheckResult{
@@ -82,25 +135,15 @@ func (c *HelmChecker) Check(config v1.HelmCheck) *pkg.CheckResult {
 		}
 	}
 
-	if err != nil {
-		return &pkg.CheckResult{
-			Check:    config,
-			Pass:     false,
-			Invalid:  true,
-			Duration: 0,
-			Message:  fmt.Sprintf("Failed to get user: %v", err),
+	defer func() {
+		if err := cleanUp(testChartName, chartmuseum, config); err != nil {
+			logger.Warnf("Failed to perform cleanup: %v", err)
 		}
-	}
-
-	defer os.RemoveAll("./test-chart-0.1.0.tgz")
+	}()
 
-	iCli := action.NewPull()
-	if config.CaFile != nil {
-		iCli.CaFile = *config.CaFile
-	}
-	kubeconfigPath := pkg.GetKubeconfig()
-	iCli.Settings = &cli.EnvSettings{
-		KubeConfig: kubeconfigPath,
+	httpClient, err := helmHTTPClient(config)
+	if err != nil {
+		return invalidErrorf(config, err, "invalid cafile")
 	}
 
 	logger.Tracef("Pulling test chart")
@@ -114,8 +157,11 @@ func (c *HelmChecker) Check(config v1.HelmCheck) *pkg.CheckResult {
 			Message:  fmt.Sprintf("Failed to parse chartmuseum url: %v", err),
 		}
 	}
-	url.Path = path.Join(url.Path, "charts/test-chart-0.1.0.tgz")
-	_, err = iCli.Run(url.String())
+	url.Path = path.Join(url.Path, "charts", filepath.Base(chartPath))
+	archive, err := helmGet(ctx, httpClient, config, url.String())
+	if err == nil {
+		_, err = loader.LoadArchive(bytes.NewReader(archive))
+	}
 	if err != nil {
 		downloadOK = false
 		return &pkg.CheckResult{
@@ -123,14 +169,10 @@ func (c *HelmChecker) Check(config v1.HelmCheck) *pkg.CheckResult {
 			Pass:     false,
 			Invalid:  false,
 			Duration: 0,
+			Message:  fmt.Sprintf("Failed to download %s: %v", url, err),
 		}
 	}
 
-	defer cleanUp("test-chart", chartmuseum, config)
-
-	if err != nil {
-		logger.Warnf("Failed to perform cleanup: %v", err)
-	}
 	elapsed := time.Since(start)
 	return &pkg.CheckResult{
 		Check:    config,
@@ -140,30 +182,287 @@ func (c *HelmChecker) Check(config v1.HelmCheck) *pkg.CheckResult {
 	}
 }
 
-func cleanUp(chartname string, chartmuseum string, config v1.HelmCheck) error {
-	caCert, err := ioutil.ReadFile(*config.CaFile)
+// checkRepository validates the index.yaml of a plain chart repository and
+// downloads a chart from it, verifying its digest
+func (c *HelmChecker) checkRepository(ctx context.Context, config v1.HelmCheck) *pkg.CheckResult {
+	client, err := helmHTTPClient(config)
 	if err != nil {
-		return fmt.Errorf("failed to read certificate file: %v", err)
+		return invalidErrorf(config, err, "invalid cafile")
 	}
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(caCert)
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: caCertPool,
+	dir, err := ioutil.TempDir("", "canary_checker_helm")
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to create temp directory")
+	}
+	defer os.RemoveAll(dir)
+
+	timer := NewTimer()
+	index, err := fetchIndex(ctx, client, config, dir)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	indexTime := timer.Elapsed()
+
+	downloadTimer := NewTimer()
+	chartVersion, archive, err := fetchChart(ctx, client, config, index)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	downloadTime := downloadTimer.Elapsed()
+
+	result := &pkg.CheckResult{
+		Check:    config,
+		Pass:     true,
+		Duration: int64(timer.Elapsed()),
+		Message:  fmt.Sprintf("%s-%s", chartVersion.Name, chartVersion.Version),
+		Metrics: []pkg.Metric{
+			{
+				Name:   "index_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"url": config.URL},
+				Value:  indexTime,
+			},
+			{
+				Name:   "download_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"url": config.URL},
+				Value:  downloadTime,
+			},
+		},
+	}
+	if chartVersion.Digest != "" {
+		sum := sha256.Sum256(archive)
+		if digest := hex.EncodeToString(sum[:]); digest != chartVersion.Digest {
+			return failWithMetrics(result, "digest of %s-%s does not match: %s != %s", chartVersion.Name, chartVersion.Version, digest, chartVersion.Digest)
+		}
+	}
+	if _, err := loader.LoadArchive(bytes.NewReader(archive)); err != nil {
+		return failWithMetrics(result, "invalid chart %s-%s: %v", chartVersion.Name, chartVersion.Version, err)
+	}
+	return result
+}
+
+// fetchIndex downloads and parses the index.yaml of the chart repository at config.URL
+func fetchIndex(ctx context.Context, client *http.Client, config v1.HelmCheck, dir string) (*repo.IndexFile, error) {
+	indexURL := strings.TrimSuffix(config.URL, "/") + "/index.yaml"
+	data, err := helmGet(ctx, client, config, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	indexPath := filepath.Join(dir, "index.yaml")
+	if err := ioutil.WriteFile(indexPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write index: %v", err)
+	}
+	index, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index %s: %v", indexURL, err)
+	}
+	if len(index.Entries) == 0 {
+		return nil, fmt.Errorf("index %s does not contain any charts", indexURL)
+	}
+	return index, nil
+}
+
+// fetchChart downloads config.Chart at config.Version, defaulting to the latest version
+// of the first chart in the index
+func fetchChart(ctx context.Context, client *http.Client, config v1.HelmCheck, index *repo.IndexFile) (*repo.ChartVersion, []byte, error) {
+	chartName := config.Chart
+	if chartName == "" {
+		var names []string
+		for name := range index.Entries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		chartName = names[0]
+	}
+	chartVersion, err := index.Get(chartName, config.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("chart %s %s not found in %s", chartName, config.Version, config.URL)
+	}
+	if len(chartVersion.URLs) == 0 {
+		return nil, nil, fmt.Errorf("chart %s %s has no urls", chartName, chartVersion.Version)
+	}
+	chartURL, err := repo.ResolveReferenceURL(config.URL, chartVersion.URLs[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid url for chart %s %s: %v", chartName, chartVersion.Version, err)
+	}
+	archive, err := helmGet(ctx, client, config, chartURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chartVersion, archive, nil
+}
+
+// checkOCI pushes a test chart to an OCI registry, pulls it back to verify its digest
+// and deletes it again
+func (c *HelmChecker) checkOCI(ctx context.Context, config v1.HelmCheck) *pkg.CheckResult {
+	ref, err := parseImageReference(strings.TrimSuffix(strings.TrimPrefix(config.URL, "oci://"), "/") + "/" + testChartName)
+	if err != nil {
+		return invalidErrorf(config, err, "invalid url")
+	}
+	dir, err := ioutil.TempDir("", "canary_checker_helm")
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to create temp directory")
+	}
+	defer os.RemoveAll(dir)
+
+	// OCI tags cannot contain +, so a unique prerelease version is used instead
+	ref.Reference = fmt.Sprintf("0.1.0-%d", time.Now().Unix())
+	chartPath, err := createTestChart(dir, ref.Reference)
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to create test chart")
+	}
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to load test chart")
+	}
+	archive, err := ioutil.ReadFile(chartPath)
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to read test chart")
+	}
+	chartConfig, err := json.Marshal(chart.Metadata)
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to generate chart config")
+	}
+
+	registry := newRegistryClient(ctx, ref, config.Username, config.Password, config.SkipTLSVerify, config.PlainHTTP)
+	timer := NewTimer()
+	pushTimer := NewTimer()
+	chartDigest, err := registry.UploadBlob(archive)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	configDigest, err := registry.UploadBlob(chartConfig)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	body, err := json.Marshal(manifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIManifest,
+		Config:        &descriptor{MediaType: mediaTypeHelmConfig, Size: int64(len(chartConfig)), Digest: configDigest},
+		Layers:        []descriptor{{MediaType: mediaTypeHelmChart, Size: int64(len(archive)), Digest: chartDigest}},
+	})
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to generate manifest")
+	}
+	digest, err := registry.PutManifest(ref.Reference, mediaTypeOCIManifest, body)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	pushTime := pushTimer.Elapsed()
+
+	deleteChart := func() {
+		if err := registry.DeleteManifest(digest); err != nil {
+			logger.Errorf("failed to delete %s: %v", ref, err)
+		}
+	}
+	pullTimer := NewTimer()
+	pulled, pulledDigest, err := registry.Manifest(ref.Reference)
+	if err != nil {
+		deleteChart()
+		return Failf(config, "failed to pull %s: %v", ref, err)
+	}
+	if pulledDigest != digest || len(pulled.Layers) != 1 || pulled.Layers[0].Digest != chartDigest {
+		deleteChart()
+		return Failf(config, "pulled %s with digest %s, expected %s", ref, pulledDigest, digest)
+	}
+	if _, err := registry.Blob(chartDigest); err != nil {
+		deleteChart()
+		return Failf(config, "%v", err)
+	}
+	pullTime := pullTimer.Elapsed()
+
+	deleteTimer := NewTimer()
+	if err := registry.DeleteManifest(digest); err != nil {
+		return Failf(config, "failed to delete %s: %v", ref, err)
+	}
+	deleteTime := deleteTimer.Elapsed()
+
+	return &pkg.CheckResult{
+		Check:    config,
+		Pass:     true,
+		Duration: int64(timer.Elapsed()),
+		Message:  ref.String(),
+		Metrics: []pkg.Metric{
+			{
+				Name:   "push_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"url": config.URL},
+				Value:  pushTime,
+			},
+			{
+				Name:   "pull_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"url": config.URL},
+				Value:  pullTime,
+			},
+			{
+				Name:   "delete_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"url": config.URL},
+				Value:  deleteTime,
 			},
 		},
 	}
+}
+
+func helmHTTPClient(config v1.HelmCheck) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.SkipTLSVerify}
+	if config.CaFile != nil && *config.CaFile != "" {
+		caCert, err := ioutil.ReadFile(*config.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate file: %v", err)
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = caCertPool
+	}
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+func helmGet(ctx context.Context, client *http.Client, config v1.HelmCheck, url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %s: %v", url, err)
+	}
+	if config.Username != "" {
+		req.SetBasicAuth(config.Username, config.Password)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", url, err)
+	}
+	return data, nil
+}
+
+func cleanUp(chartname string, chartmuseum string, config v1.HelmCheck) error {
+	client, err := helmHTTPClient(config)
+	if err != nil {
+		return err
+	}
 	url, err := url.Parse(chartmuseum)
 	if err != nil {
 		return fmt.Errorf("Failed to parse chartmuseum url: %v", err)
 	}
 	url.Path = path.Join("api", url.Path, "charts", chartname)
 	req, err := http.NewRequest("DELETE", url.String(), nil)
-	req.SetBasicAuth(config.Username, config.Password)
 	if err != nil {
 		return fmt.Errorf("failed to create DELETE request: %v", err)
 	}
+	req.SetBasicAuth(config.Username, config.Password)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -176,20 +475,18 @@ func cleanUp(chartname string, chartmuseum string, config v1.HelmCheck) error {
 	return nil
 }
 
-func createTestChart() (*string, error) {
-	dir, err := ioutil.TempDir("/tmp", "canary_checker_helm_test_chart")
-	if err != nil {
-		return nil, fmt.Errorf("createTestChart: failed to create temp directory: %v", err)
-	}
-	chartDir, err := chartutil.Create("test-chart", dir)
+// createTestChart packages a new test chart into dir, overriding its version if not empty
+func createTestChart(dir, version string) (string, error) {
+	chartDir, err := chartutil.Create(testChartName, dir)
 	if err != nil {
-		return nil, fmt.Errorf("createTestChart: failed to create test chart: %v", err)
+		return "", fmt.Errorf("createTestChart: failed to create test chart: %v", err)
 	}
 	packageAction := action.NewPackage()
+	packageAction.Destination = dir
+	packageAction.Version = version
 	packagePath, err := packageAction.Run(chartDir, make(map[string]interface{}))
 	if err != nil {
-		return nil, fmt.Errorf("createTestChart: failed to package test chart: %v", err)
+		return "", fmt.Errorf("createTestChart: failed to package test chart: %v", err)
 	}
-	defer os.RemoveAll(dir)
-	return &packagePath, nil
+	return packagePath, nil
 }
@@ -0,0 +1,225 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	perrors "github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	helmCheckSelector = "canary-checker.flanksource.com/helmCheck"
+
+	defaultHelmNamespace        = "canary-helm"
+	defaultHelmInstallTimeout   = 5 * time.Minute
+	defaultHelmTestTimeout      = 5 * time.Minute
+	defaultHelmUninstallTimeout = 2 * time.Minute
+	defaultHelmLogLines         = 20
+)
+
+// checkInstall installs a chart from a chart repository into a throwaway namespace,
+// waits for its resources to become ready, runs its test hooks and uninstalls it again
+func (c *HelmChecker) checkInstall(ctx context.Context, config v1.HelmCheck) *pkg.CheckResult {
+	if config.URL == "" || config.Chart == "" {
+		return invalidErrorf(config, fmt.Errorf("url and chart are required"), "invalid install check")
+	}
+	values, err := chartutil.ReadValues([]byte(config.Values))
+	if err != nil {
+		return invalidErrorf(config, err, "invalid values")
+	}
+	client, err := helmHTTPClient(config)
+	if err != nil {
+		return invalidErrorf(config, err, "invalid cafile")
+	}
+	k8s, err := pkg.NewK8sClient()
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to create k8s client")
+	}
+	dir, err := ioutil.TempDir("", "canary_checker_helm")
+	if err != nil {
+		return unexpectedErrorf(config, err, "failed to create temp directory")
+	}
+	defer os.RemoveAll(dir)
+
+	timer := NewTimer()
+	index, err := fetchIndex(ctx, client, config, dir)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	chartVersion, archive, err := fetchChart(ctx, client, config, index)
+	if err != nil {
+		return Failf(config, "%v", err)
+	}
+	chart, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return Failf(config, "invalid chart %s-%s: %v", chartVersion.Name, chartVersion.Version, err)
+	}
+
+	prefix := config.Namespace
+	if prefix == "" {
+		prefix = defaultHelmNamespace
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-%s", prefix, utilrand.String(5)),
+			Labels: map[string]string{helmCheckSelector: chartVersion.Name},
+		},
+	}
+	if _, err := k8s.CoreV1().Namespaces().Create(ns); err != nil {
+		return unexpectedErrorf(config, err, "unable to create namespace")
+	}
+	defer func() {
+		if err := k8s.CoreV1().Namespaces().Delete(ns.Name, nil); err != nil {
+			logger.Errorf("Failed to delete namespace %s: %v", ns.Name, err)
+		}
+	}()
+
+	flags := genericclioptions.NewConfigFlags(false)
+	kubeconfig := pkg.GetKubeconfig()
+	flags.KubeConfig = &kubeconfig
+	flags.Namespace = &ns.Name
+	actionConfig := &action.Configuration{}
+	if err := actionConfig.Init(flags, ns.Name, "secret", logger.Debugf); err != nil {
+		return unexpectedErrorf(config, err, "failed to initialize helm")
+	}
+	releaseName := chartVersion.Name
+
+	install := action.NewInstall(actionConfig)
+	install.Namespace = ns.Name
+	install.ReleaseName = releaseName
+	install.Wait = true
+	// helm actions do not accept a context, so they time out with the check instead
+	install.Timeout = capTimeout(ctx, helmTimeout(config.InstallTimeout, defaultHelmInstallTimeout))
+	installTimer := NewTimer()
+	if _, err := install.Run(chart, values); err != nil {
+		uninstallRelease(actionConfig, config, releaseName)
+		return Failf(config, "failed to install %s-%s: %v", chartVersion.Name, chartVersion.Version, err)
+	}
+	installTime := installTimer.Elapsed()
+
+	test := action.NewReleaseTesting(actionConfig)
+	test.Namespace = ns.Name
+	test.Timeout = capTimeout(ctx, helmTimeout(config.TestTimeout, defaultHelmTestTimeout))
+	testTimer := NewTimer()
+	rel, err := test.Run(releaseName)
+	testTime := testTimer.Elapsed()
+	if err != nil {
+		message := fmt.Sprintf("tests of %s-%s failed: %v", chartVersion.Name, chartVersion.Version, err)
+		if rel != nil {
+			if logs := failedHookLogs(k8s, config, rel); logs != "" {
+				message += " " + logs
+			}
+		}
+		uninstallRelease(actionConfig, config, releaseName)
+		return Failf(config, "%s", message)
+	}
+
+	uninstallTimer := NewTimer()
+	if err := uninstallRelease(actionConfig, config, releaseName); err != nil {
+		return Failf(config, "failed to uninstall %s-%s: %v", chartVersion.Name, chartVersion.Version, err)
+	}
+	uninstallTime := uninstallTimer.Elapsed()
+
+	labels := map[string]string{"chart": chartVersion.Name}
+	return &pkg.CheckResult{
+		Check:    config,
+		Pass:     true,
+		Duration: int64(timer.Elapsed()),
+		Message:  fmt.Sprintf("%s-%s", chartVersion.Name, chartVersion.Version),
+		Metrics: []pkg.Metric{
+			{
+				Name:   "install_time",
+				Type:   metrics.HistogramType,
+				Labels: labels,
+				Value:  installTime,
+			},
+			{
+				Name:   "test_time",
+				Type:   metrics.HistogramType,
+				Labels: labels,
+				Value:  testTime,
+			},
+			{
+				Name:   "uninstall_time",
+				Type:   metrics.HistogramType,
+				Labels: labels,
+				Value:  uninstallTime,
+			},
+		},
+	}
+}
+
+func uninstallRelease(actionConfig *action.Configuration, config v1.HelmCheck, name string) error {
+	uninstall := action.NewUninstall(actionConfig)
+	uninstall.Timeout = helmTimeout(config.UninstallTimeout, defaultHelmUninstallTimeout)
+	if _, err := uninstall.Run(name); err != nil {
+		logger.Errorf("Failed to uninstall release %s: %v", name, err)
+		return perrors.Wrapf(err, "failed to uninstall release %s", name)
+	}
+	return nil
+}
+
+// failedHookLogs returns the last log lines of every failed test hook pod of the release
+func failedHookLogs(k8s *kubernetes.Clientset, config v1.HelmCheck, rel *release.Release) string {
+	lines := int64(defaultHelmLogLines)
+	if config.LogLines > 0 {
+		lines = config.LogLines
+	}
+	var msg []string
+	for _, hook := range rel.Hooks {
+		if hook.Kind != "Pod" || hook.LastRun.Phase != release.HookPhaseFailed || !isTestHook(hook) {
+			continue
+		}
+		msg = append(msg, fmt.Sprintf("[pod=%s]", hook.Name))
+		logs, err := k8s.CoreV1().Pods(rel.Namespace).GetLogs(hook.Name, &corev1.PodLogOptions{TailLines: &lines}).Do().Raw()
+		if err != nil {
+			logger.Debugf("failed to get logs of pod %s: %v", hook.Name, err)
+			continue
+		}
+		if len(logs) > 0 {
+			msg = append(msg, strings.TrimSpace(string(logs)))
+		}
+	}
+	return strings.Join(msg, " ")
+}
+
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
+
+// installDeadline returns the time an install check may take to install, test and uninstall its chart
+func installDeadline(config v1.HelmCheck) time.Duration {
+	return helmTimeout(config.InstallTimeout, defaultHelmInstallTimeout) +
+		helmTimeout(config.TestTimeout, defaultHelmTestTimeout) +
+		helmTimeout(config.UninstallTimeout, defaultHelmUninstallTimeout)
+}
+
+func helmTimeout(millis int64, defaultTimeout time.Duration) time.Duration {
+	if millis > 0 {
+		return time.Duration(millis) * time.Millisecond
+	}
+	return defaultTimeout
+}
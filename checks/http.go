@@ -1,6 +1,7 @@
 package checks
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
@@ -49,25 +50,28 @@ func (c *HttpChecker) Type() string {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *HttpChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *HttpChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.HTTP {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 
 }
 
 // CheckConfig : Check every record of DNS name against config information
 // Returns check result and metrics
-func (c *HttpChecker) Check(check v1.HTTPCheck) *pkg.CheckResult {
+func (c *HttpChecker) Check(ctx context.Context, check v1.HTTPCheck) *pkg.CheckResult {
 	endpoint := check.Endpoint
-	lookupResult, err := DNSLookup(endpoint)
+	lookupResult, err := DNSLookup(ctx, endpoint)
 	if err != nil {
 		return Failf(check, "failed to resolve DNS")
 	}
 	for _, urlObj := range lookupResult {
-		checkResults, err := c.checkHTTP(urlObj)
+		checkResults, err := c.checkHTTP(ctx, urlObj)
 		if err != nil {
 			return invalidErrorf(check, err, "")
 		}
@@ -115,7 +119,7 @@ func (c *HttpChecker) Check(check v1.HTTPCheck) *pkg.CheckResult {
 	return Failf(check, "No DNS results found")
 }
 
-func (c *HttpChecker) checkHTTP(urlObj pkg.URL) (*HTTPCheckResult, error) {
+func (c *HttpChecker) checkHTTP(ctx context.Context, urlObj pkg.URL) (*HTTPCheckResult, error) {
 	var exp time.Time
 	start := time.Now()
 	var urlString string
@@ -143,7 +147,7 @@ func (c *HttpChecker) checkHTTP(urlObj pkg.URL) (*HTTPCheckResult, error) {
 
 	req.Host = urlObj.Host
 	req.Header.Add("Host", urlObj.Host)
-	resp, err := client.Do(req)
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +183,7 @@ func (c *HttpChecker) checkHTTP(urlObj pkg.URL) (*HTTPCheckResult, error) {
 	return &checkResult, nil
 }
 
-func DNSLookup(endpoint string) ([]pkg.URL, error) {
+func DNSLookup(ctx context.Context, endpoint string) ([]pkg.URL, error) {
 	if net.ParseIP(endpoint) != nil {
 		return []pkg.URL{pkg.URL{IP: endpoint}}, nil
 	}
@@ -188,11 +192,12 @@ func DNSLookup(endpoint string) ([]pkg.URL, error) {
 	if err != nil {
 		return nil, err
 	}
-	ips, err := net.LookupIP(parsedURL.Hostname())
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, parsedURL.Hostname())
 	if err != nil {
 		return nil, err
 	}
-	for _, ip := range ips {
+	for _, addr := range addrs {
+		ip := addr.IP
 		if ip.To4() == nil {
 			continue
 		}
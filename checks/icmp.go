@@ -1,6 +1,7 @@
 package checks
 
 import (
+	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -33,25 +34,28 @@ func (c *IcmpChecker) Type() string {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *IcmpChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *IcmpChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.ICMP {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // CheckConfig : Check every record of DNS name against config information
 // Returns check result and metrics
-func (c *IcmpChecker) Check(check v1.ICMPCheck) *pkg.CheckResult {
+func (c *IcmpChecker) Check(ctx context.Context, check v1.ICMPCheck) *pkg.CheckResult {
 	endpoint := check.Endpoint
 
-	lookupResult, err := DNSLookup(endpoint)
+	lookupResult, err := DNSLookup(ctx, endpoint)
 	if err != nil {
 		return invalidErrorf(check, err, "unable to resolve dns")
 	}
 	for _, urlObj := range lookupResult {
-		pingerStats, err := c.checkICMP(urlObj, check.PacketCount)
+		pingerStats, err := c.checkICMP(ctx, urlObj, check.PacketCount)
 		if err != nil {
 			return Failf(check, "Failed to check icmp: %v", err)
 		}
@@ -81,7 +85,7 @@ func (c *IcmpChecker) Check(check v1.ICMPCheck) *pkg.CheckResult {
 
 }
 
-func (c *IcmpChecker) checkICMP(urlObj pkg.URL, packetCount int) (*ping.Statistics, error) {
+func (c *IcmpChecker) checkICMP(ctx context.Context, urlObj pkg.URL, packetCount int) (*ping.Statistics, error) {
 	ip := urlObj.IP
 	pinger, err := ping.NewPinger(ip)
 	if err != nil {
@@ -92,7 +96,7 @@ func (c *IcmpChecker) checkICMP(urlObj pkg.URL, packetCount int) (*ping.Statisti
 	// whitelist the sysctl's for use
 	pinger.SetPrivileged(true)
 	pinger.Count = packetCount
-	pinger.Timeout = time.Second * 10
+	pinger.Timeout = capTimeout(ctx, time.Second*10)
 	pinger.Run()
 	return pinger.Statistics(), nil
 }
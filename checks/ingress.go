@@ -0,0 +1,296 @@
+package checks
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	perrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// ingress API versions in order of preference
+var ingressVersions = []schema.GroupVersion{
+	{Group: "networking.k8s.io", Version: "v1"},
+	{Group: "networking.k8s.io", Version: "v1beta1"},
+	{Group: "extensions", Version: "v1beta1"},
+}
+
+// Gateway API versions in order of preference
+var httpRouteVersions = []schema.GroupVersion{
+	{Group: "gateway.networking.k8s.io", Version: "v1"},
+	{Group: "gateway.networking.k8s.io", Version: "v1beta1"},
+}
+
+// ingressRoute describes how a service created by a check is exposed on IngressHost
+type ingressRoute struct {
+	Name      string
+	Namespace string
+	Host      string
+	Path      string
+	Service   string
+	Port      int64
+	Class     string
+	TLSSecret string
+	// Gateway to attach an HTTPRoute to as namespace/name, an ingress is used if empty
+	Gateway string
+}
+
+// ingressExposer exposes services through an Ingress or an HTTPRoute, using
+// the newest API version advertised by the cluster
+type ingressExposer struct {
+	k8s     *kubernetes.Clientset
+	dynamic dynamic.Interface
+}
+
+func (e *ingressExposer) Expose(route ingressRoute) error {
+	if e == nil || e.k8s == nil || e.dynamic == nil {
+		return fmt.Errorf("Connection to k8s not established")
+	}
+	if route.Gateway != "" {
+		return e.exposeHTTPRoute(route)
+	}
+	return e.exposeIngress(route)
+}
+
+// servedVersion returns the first version serving resource, in order of preference
+func (e *ingressExposer) servedVersion(resource string, versions []schema.GroupVersion) (schema.GroupVersionResource, error) {
+	for _, gv := range versions {
+		resources, err := e.k8s.Discovery().ServerResourcesForGroupVersion(gv.String())
+		if err != nil {
+			continue
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == resource {
+				return gv.WithResource(resource), nil
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("%s are not served by the cluster", resource)
+}
+
+func (e *ingressExposer) exposeIngress(route ingressRoute) error {
+	gvr, err := e.servedVersion("ingresses", ingressVersions)
+	if err != nil {
+		return err
+	}
+	ingresses := e.dynamic.Resource(gvr).Namespace(route.Namespace)
+
+	ingress, err := ingresses.Get(route.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return perrors.Wrapf(err, "Failed to get ingress %s in namespace %s", route.Name, route.Namespace)
+	} else if err == nil {
+		if err := setIngressRoute(ingress, gvr, route); err != nil {
+			return perrors.Wrapf(err, "invalid ingress %s in namespace %s", route.Name, route.Namespace)
+		}
+		if _, err := ingresses.Update(ingress, metav1.UpdateOptions{}); err != nil {
+			return perrors.Wrapf(err, "failed to update ingress %s in namespace %s", route.Name, route.Namespace)
+		}
+		return nil
+	}
+
+	ingress = &unstructured.Unstructured{Object: map[string]interface{}{}}
+	ingress.SetAPIVersion(gvr.GroupVersion().String())
+	ingress.SetKind("Ingress")
+	ingress.SetName(route.Name)
+	ingress.SetNamespace(route.Namespace)
+	if err := setIngressRoute(ingress, gvr, route); err != nil {
+		return err
+	}
+	if _, err := ingresses.Create(ingress, metav1.CreateOptions{}); err != nil {
+		return perrors.Wrapf(err, "failed to create ingress %s in namespace %s", route.Name, route.Namespace)
+	}
+	return nil
+}
+
+// setIngressRoute points the path of the rule matching the route host to the route service,
+// adding the rule or path if they don't exist yet. Other rules and paths are left untouched.
+func setIngressRoute(ingress *unstructured.Unstructured, gvr schema.GroupVersionResource, route ingressRoute) error {
+	var backend map[string]interface{}
+	if gvr.GroupVersion() == ingressVersions[0] {
+		backend = map[string]interface{}{
+			"service": map[string]interface{}{
+				"name": route.Service,
+				"port": map[string]interface{}{"number": route.Port},
+			},
+		}
+	} else {
+		backend = map[string]interface{}{
+			"serviceName": route.Service,
+			"servicePort": route.Port,
+		}
+	}
+
+	path := route.Path
+	if path == "" {
+		path = "/"
+	}
+	rules, _, err := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+	if err != nil {
+		return err
+	}
+	rule := findOrAppend(&rules, "host", route.Host)
+	paths, _, err := unstructured.NestedSlice(rule, "http", "paths")
+	if err != nil {
+		return err
+	}
+	httpPath := findOrAppend(&paths, "path", path)
+	httpPath["backend"] = backend
+	if gvr.GroupVersion() == ingressVersions[0] {
+		if _, ok := httpPath["pathType"]; !ok {
+			httpPath["pathType"] = "Prefix"
+		}
+	}
+	rule["http"] = map[string]interface{}{"paths": paths}
+	if err := unstructured.SetNestedSlice(ingress.Object, rules, "spec", "rules"); err != nil {
+		return err
+	}
+
+	if route.Class != "" {
+		if gvr.GroupVersion() == ingressVersions[0] {
+			if err := unstructured.SetNestedField(ingress.Object, route.Class, "spec", "ingressClassName"); err != nil {
+				return err
+			}
+		} else {
+			annotations := ingress.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[ingressClassAnnotation] = route.Class
+			ingress.SetAnnotations(annotations)
+		}
+	}
+
+	if route.TLSSecret != "" {
+		tlsEntries, _, err := unstructured.NestedSlice(ingress.Object, "spec", "tls")
+		if err != nil {
+			return err
+		}
+		entry := findOrAppend(&tlsEntries, "secretName", route.TLSSecret)
+		hosts, _, _ := unstructured.NestedStringSlice(entry, "hosts")
+		if !containsString(hosts, route.Host) {
+			hosts = append(hosts, route.Host)
+		}
+		entry["hosts"] = toInterfaceSlice(hosts)
+		if err := unstructured.SetNestedSlice(ingress.Object, tlsEntries, "spec", "tls"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ingressExposer) exposeHTTPRoute(route ingressRoute) error {
+	gvr, err := e.servedVersion("httproutes", httpRouteVersions)
+	if err != nil {
+		return err
+	}
+	routes := e.dynamic.Resource(gvr).Namespace(route.Namespace)
+
+	parentRef := map[string]interface{}{"name": route.Gateway}
+	if parts := strings.SplitN(route.Gateway, "/", 2); len(parts) == 2 {
+		parentRef = map[string]interface{}{"namespace": parts[0], "name": parts[1]}
+	}
+	path := route.Path
+	if path == "" {
+		path = "/"
+	}
+	// the HTTPRoute is owned by the check, so its spec is always replaced
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  []interface{}{route.Host},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{"type": "PathPrefix", "value": path},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": route.Service, "port": route.Port},
+				},
+			},
+		},
+	}
+
+	httpRoute, err := routes.Get(route.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return perrors.Wrapf(err, "Failed to get httproute %s in namespace %s", route.Name, route.Namespace)
+	} else if err == nil {
+		httpRoute.Object["spec"] = spec
+		if _, err := routes.Update(httpRoute, metav1.UpdateOptions{}); err != nil {
+			return perrors.Wrapf(err, "failed to update httproute %s in namespace %s", route.Name, route.Namespace)
+		}
+		return nil
+	}
+
+	httpRoute = &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	httpRoute.SetAPIVersion(gvr.GroupVersion().String())
+	httpRoute.SetKind("HTTPRoute")
+	httpRoute.SetName(route.Name)
+	httpRoute.SetNamespace(route.Namespace)
+	if _, err := routes.Create(httpRoute, metav1.CreateOptions{}); err != nil {
+		return perrors.Wrapf(err, "failed to create httproute %s in namespace %s", route.Name, route.Namespace)
+	}
+	return nil
+}
+
+// ingressURL returns the URL to probe, using HTTPS if the route is secured with a TLS secret
+func ingressURL(host, path, tlsSecret string) string {
+	scheme := "http"
+	if tlsSecret != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
+
+// insecureIngressClient is shared by the checks of ingresses that skip TLS verification, so that polling
+// an ingress reuses its connections
+var insecureIngressClient = &http.Client{
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	},
+}
+
+func ingressHTTPClient(skipTLSVerify bool) *http.Client {
+	if !skipTLSVerify {
+		return http.DefaultClient
+	}
+	return insecureIngressClient
+}
+
+// findOrAppend returns the map in items with key set to value, appending a new one if none exists
+func findOrAppend(items *[]interface{}, key, value string) map[string]interface{} {
+	for _, item := range *items {
+		if m, ok := item.(map[string]interface{}); ok && m[key] == value {
+			return m
+		}
+	}
+	m := map[string]interface{}{key: value}
+	*items = append(*items, m)
+	return m
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func toInterfaceSlice(items []string) []interface{} {
+	var out []interface{}
+	for _, item := range items {
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,344 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	canaryv1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	perrors "github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"golang.org/x/sync/semaphore"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	jobCheckSelector = "canary-checker.flanksource.com/jobCheck"
+
+	defaultJobLogLines        = 20
+	defaultJobScheduleTimeout = 60 * time.Second
+	defaultJobDeadline        = 5 * time.Minute
+	defaultJobDeleteTimeout   = 60 * time.Second
+)
+
+type JobChecker struct {
+	lock *semaphore.Weighted
+	k8s  *kubernetes.Clientset
+	ng   *NameGenerator
+}
+
+func NewJobChecker() *JobChecker {
+	jc := &JobChecker{
+		lock: semaphore.NewWeighted(1),
+		ng:   &NameGenerator{PodsCount: 20},
+	}
+
+	k8sClient, err := pkg.NewK8sClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return jc
+	}
+
+	jc.k8s = k8sClient
+
+	return jc
+}
+
+// Type: returns checker type
+func (c *JobChecker) Type() string {
+	return "job"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *JobChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newSerialCheckGroup(ctx, config)
+	for _, conf := range config.Job {
+		conf := conf
+		group.RunWithTimeout(conf, deadlineTimeout(config, conf, conf.Deadline), func(ctx context.Context) *pkg.CheckResult {
+			// the deadline only starts once the check is running
+			deadline, _ := ctx.Deadline()
+			return c.Check(conf, deadline)
+		})
+	}
+	return group.Wait()
+}
+
+func (c *JobChecker) newJob(jobCheck canaryv1.JobCheck) (*batchv1.Job, error) {
+	if jobCheck.Spec == "" {
+		return nil, fmt.Errorf("Job spec cannot be empty")
+	}
+
+	job := &batchv1.Job{}
+	if err := yaml.Unmarshal([]byte(jobCheck.Spec), job); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshall job spec: %v", err)
+	}
+
+	job.Name = c.ng.PodName(job.Name + "-")
+	job.Namespace = jobCheck.Namespace
+	if job.Labels == nil {
+		job.Labels = make(map[string]string)
+	}
+	job.Labels[jobCheckSelector] = c.jobCheckSelectorValue(jobCheck)
+	job.Labels[podGeneralSelector] = "true"
+	if job.Spec.Template.Labels == nil {
+		job.Spec.Template.Labels = make(map[string]string)
+	}
+	job.Spec.Template.Labels[jobCheckSelector] = c.jobCheckSelectorValue(jobCheck)
+	job.Spec.Template.Labels[podGeneralSelector] = "true"
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = v1.RestartPolicyNever
+	}
+	// a failing smoke test should be reported on the first failure rather than retried
+	if job.Spec.BackoffLimit == nil {
+		backoffLimit := int32(0)
+		job.Spec.BackoffLimit = &backoffLimit
+	}
+	return job, nil
+}
+
+// Check : Create the Job and wait for it to run to completion
+// Returns check result and metrics
+func (c *JobChecker) Check(jobCheck canaryv1.JobCheck, checkDeadline time.Time) *pkg.CheckResult {
+	if !c.lock.TryAcquire(1) {
+		logger.Tracef("Check already in progress, skipping")
+		return nil
+	}
+	defer func() { c.lock.Release(1) }()
+
+	// checks without a deadline, timeout or interval are not bounded by their context
+	if checkDeadline.IsZero() {
+		checkDeadline = time.Now().Add(defaultJobDeadline)
+	}
+
+	if err := c.Cleanup(jobCheck); err != nil {
+		return unexpectedErrorf(jobCheck, err, "failed to cleanup old artifacts")
+	}
+
+	job, err := c.newJob(jobCheck)
+	if err != nil {
+		return invalidErrorf(jobCheck, err, "invalid job spec")
+	}
+
+	startTimer := NewTimer()
+	logger.Debugf("Running job check %s", jobCheck.Name)
+
+	jobs := c.k8s.BatchV1().Jobs(jobCheck.Namespace)
+	if _, err := jobs.Create(job); err != nil {
+		return unexpectedErrorf(jobCheck, err, "unable to create job")
+	}
+
+	var deleteTime float64
+	defer func() {
+		if deleteTime == 0 {
+			if _, err := c.deleteJob(jobCheck, job.Name); err != nil {
+				logger.Errorf("failed to delete job %s: %v", job.Name, err)
+			}
+		}
+	}()
+
+	scheduleTimeout := defaultJobScheduleTimeout
+	if jobCheck.ScheduleTimeout > 0 {
+		scheduleTimeout = time.Duration(jobCheck.ScheduleTimeout) * time.Millisecond
+	}
+	scheduleTime, err := c.waitForScheduling(jobCheck, job.Name, scheduleTimeout)
+	if err != nil {
+		message := err.Error()
+		if podFailMessage := c.jobFailMessage(jobCheck, job.Name); podFailMessage != "" {
+			message = message + " " + podFailMessage
+		}
+		return Failf(jobCheck, "%s", message)
+	}
+
+	runTimer := NewTimer()
+	job, err = c.WaitForJob(jobCheck.Namespace, job.Name, checkDeadline)
+	runTime := runTimer.Elapsed()
+	pass := err == nil
+
+	var message string
+	if !pass {
+		message = err.Error()
+		if podFailMessage := c.jobFailMessage(jobCheck, job.Name); podFailMessage != "" {
+			message = message + " " + podFailMessage
+		}
+	}
+	logger.Debugf("%s scheduled=%0.0f run=%0.0f wall=%s pass=%v", job.Name, scheduleTime, runTime, startTimer, pass)
+
+	deleteTime, err = c.deleteJob(jobCheck, job.Name)
+	if err != nil {
+		return unexpectedErrorf(jobCheck, err, "failed to delete job")
+	}
+
+	return &pkg.CheckResult{
+		Check:    jobCheck,
+		Pass:     pass,
+		Duration: int64(startTimer.Elapsed()),
+		Message:  message,
+		Metrics: []pkg.Metric{
+			{
+				Name:   "schedule_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"jobCheck": jobCheck.Name},
+				Value:  scheduleTime,
+			},
+			{
+				Name:   "run_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"jobCheck": jobCheck.Name},
+				Value:  runTime,
+			},
+			{
+				Name:   "delete_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"jobCheck": jobCheck.Name},
+				Value:  deleteTime,
+			},
+		},
+	}
+}
+
+// waitForScheduling waits for the first pod of the job to be scheduled and
+// returns the time it took in milliseconds
+func (c *JobChecker) waitForScheduling(jobCheck canaryv1.JobCheck, name string, timeout time.Duration) (float64, error) {
+	timer := NewTimer()
+	start := time.Now()
+	for {
+		pods, err := c.jobPods(jobCheck, name)
+		if err != nil {
+			return 0, err
+		}
+		for _, pod := range pods {
+			if pod.Spec.NodeName != "" {
+				return timer.Elapsed(), nil
+			}
+		}
+		if start.Add(timeout).Before(time.Now()) {
+			return 0, fmt.Errorf("Timeout exceeded waiting for job %s to be scheduled", name)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// WaitForJob waits for a job to complete, or returns an error if the job
+// failed or did not complete before the deadline
+func (c *JobChecker) WaitForJob(ns, name string, deadline time.Time) (*batchv1.Job, error) {
+	jobs := c.k8s.BatchV1().Jobs(ns)
+	for {
+		job, err := jobs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}, perrors.Wrapf(err, "failed to get job %s in namespace %s", name, ns)
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != v1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return job, nil
+			case batchv1.JobFailed:
+				return job, fmt.Errorf("job %s failed: %s %s", name, condition.Reason, condition.Message)
+			}
+		}
+		if deadline.Before(time.Now()) {
+			return job, fmt.Errorf("Deadline exceeded waiting for job %s to complete, active=%d succeeded=%d failed=%d", name, job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// deleteJob deletes the job and its pods, waiting for the deletion to complete
+// and returns the time it took in milliseconds
+func (c *JobChecker) deleteJob(jobCheck canaryv1.JobCheck, name string) (float64, error) {
+	timer := NewTimer()
+	jobs := c.k8s.BatchV1().Jobs(jobCheck.Namespace)
+	propagation := metav1.DeletePropagationForeground
+	if err := jobs.Delete(name, &metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+		return 0, perrors.Wrapf(err, "Failed to delete job %s in namespace %s", name, jobCheck.Namespace)
+	}
+
+	timeout := defaultJobDeleteTimeout
+	if jobCheck.DeleteTimeout > 0 {
+		timeout = time.Duration(jobCheck.DeleteTimeout) * time.Millisecond
+	}
+	start := time.Now()
+	for {
+		_, err := jobs.Get(name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return timer.Elapsed(), nil
+		}
+		if start.Add(timeout).Before(time.Now()) {
+			return timer.Elapsed(), fmt.Errorf("Timeout exceeded waiting for job %s to be deleted", name)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+func (c *JobChecker) Cleanup(jobCheck canaryv1.JobCheck) error {
+	if c.k8s == nil {
+		return fmt.Errorf("Connection to k8s not established")
+	}
+	listOptions := metav1.ListOptions{LabelSelector: c.jobCheckSelector(jobCheck)}
+	propagation := metav1.DeletePropagationBackground
+	err := c.k8s.BatchV1().Jobs(jobCheck.Namespace).DeleteCollection(&metav1.DeleteOptions{PropagationPolicy: &propagation}, listOptions)
+	if err != nil && !errors.IsNotFound(err) {
+		return perrors.Wrapf(err, "Failed to delete jobs for check %s in namespace %s : %v", jobCheck.Name, jobCheck.Namespace, err)
+	}
+	return nil
+}
+
+func (c *JobChecker) jobPods(jobCheck canaryv1.JobCheck, name string) ([]v1.Pod, error) {
+	pods, err := c.k8s.CoreV1().Pods(jobCheck.Namespace).List(metav1.ListOptions{LabelSelector: "job-name=" + name})
+	if err != nil {
+		return nil, perrors.Wrapf(err, "failed to list pods of job %s in namespace %s", name, jobCheck.Namespace)
+	}
+	return pods.Items, nil
+}
+
+// jobFailMessage returns the status and the last log lines of every pod of the job
+func (c *JobChecker) jobFailMessage(jobCheck canaryv1.JobCheck, name string) string {
+	pods, err := c.jobPods(jobCheck, name)
+	if err != nil {
+		logger.Errorf("failed to get job fail message: %v", err)
+		return ""
+	}
+
+	lines := int64(defaultJobLogLines)
+	if jobCheck.LogLines > 0 {
+		lines = jobCheck.LogLines
+	}
+	var msg []string
+	for _, pod := range pods {
+		msg = append(msg, fmt.Sprintf("[pod=%s podPhase=%s]", pod.Name, pod.Status.Phase))
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil {
+				msg = append(msg, fmt.Sprintf("[container=%s message=%s reason=%s]", cs.Name, cs.State.Waiting.Message, cs.State.Waiting.Reason))
+			}
+		}
+		logs, err := c.k8s.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{TailLines: &lines}).Do().Raw()
+		if err != nil {
+			logger.Debugf("failed to get logs of pod %s: %v", pod.Name, err)
+			continue
+		}
+		if len(logs) > 0 {
+			msg = append(msg, strings.TrimSpace(string(logs)))
+		}
+	}
+	return strings.Join(msg, " ")
+}
+
+func (c *JobChecker) jobCheckSelectorValue(jobCheck canaryv1.JobCheck) string {
+	return fmt.Sprintf("%s.%s", jobCheck.Name, jobCheck.Namespace)
+}
+
+func (c *JobChecker) jobCheckSelector(jobCheck canaryv1.JobCheck) string {
+	return fmt.Sprintf("%s=%s", jobCheckSelector, c.jobCheckSelectorValue(jobCheck))
+}
@@ -0,0 +1,293 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	canaryv1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+type KubernetesChecker struct {
+	k8s     *kubernetes.Clientset
+	dynamic dynamic.Interface
+}
+
+func NewKubernetesChecker() *KubernetesChecker {
+	kc := &KubernetesChecker{}
+
+	k8sClient, err := pkg.NewK8sClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return kc
+	}
+	dynamicClient, err := pkg.NewDynamicClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return kc
+	}
+
+	kc.k8s = k8sClient
+	kc.dynamic = dynamicClient
+
+	return kc
+}
+
+// Type: returns checker type
+func (c *KubernetesChecker) Type() string {
+	return "kubernetes"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *KubernetesChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.Kubernetes {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : List the matching resources and verify every one of them
+// Returns check result and metrics
+func (c *KubernetesChecker) Check(ctx context.Context, check canaryv1.KubernetesCheck) *pkg.CheckResult {
+	if c.k8s == nil || c.dynamic == nil {
+		return unexpectedErrorf(check, fmt.Errorf("connection to k8s not established"), "cannot connect to API server")
+	}
+	timer := NewTimer()
+
+	resource, namespaced, err := c.findResource(check.Kind, check.APIVersion)
+	if err != nil {
+		return invalidErrorf(check, err, "unknown kind %s", check.Kind)
+	}
+	if err := ctx.Err(); err != nil {
+		return Failf(check, "cancelled: %v", err)
+	}
+
+	var client dynamic.ResourceInterface = c.dynamic.Resource(resource)
+	if namespaced && check.Namespace != "" {
+		client = c.dynamic.Resource(resource).Namespace(check.Namespace)
+	}
+	// the kubernetes client does not accept a context, so the list times out with the check instead
+	list, err := client.List(metav1.ListOptions{LabelSelector: check.LabelSelector, TimeoutSeconds: timeoutSeconds(ctx)})
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to list %s", resource.Resource)
+	}
+
+	var failures []string
+	for _, item := range list.Items {
+		if msg := verifyResource(check, item); msg != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", resourceName(item), msg))
+		}
+	}
+
+	result := &pkg.CheckResult{
+		Check:    check,
+		Pass:     true,
+		Duration: int64(timer.Elapsed()),
+		Metrics: []pkg.Metric{
+			{
+				Name:   "resource_count",
+				Type:   metrics.GaugeType,
+				Labels: map[string]string{"kind": check.Kind, "namespace": check.Namespace},
+				Value:  float64(len(list.Items)),
+			},
+			{
+				Name:   "failed_resource_count",
+				Type:   metrics.GaugeType,
+				Labels: map[string]string{"kind": check.Kind, "namespace": check.Namespace},
+				Value:  float64(len(failures)),
+			},
+		},
+	}
+
+	minCount := 1
+	if check.MinCount != nil {
+		minCount = *check.MinCount
+	}
+	if len(list.Items) < minCount {
+		return failWithMetrics(result, "found %d %s, expecting at least %d", len(list.Items), resource.Resource, minCount)
+	}
+	if len(failures) > 0 {
+		return failWithMetrics(result, "%d/%d %s failed: %s", len(failures), len(list.Items), resource.Resource, summarize(failures))
+	}
+	result.Message = fmt.Sprintf("%d %s", len(list.Items), resource.Resource)
+	return result
+}
+
+// findResource looks up the resource serving kind using the discovery API,
+// apiVersion is only required when the kind is served by multiple groups
+func (c *KubernetesChecker) findResource(kind, apiVersion string) (schema.GroupVersionResource, bool, error) {
+	var gv schema.GroupVersion
+	if apiVersion != "" {
+		parsed, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return schema.GroupVersionResource{}, false, err
+		}
+		gv = parsed
+	}
+
+	lists, err := c.k8s.Discovery().ServerPreferredResources()
+	if err != nil && len(lists) == 0 {
+		return schema.GroupVersionResource{}, false, err
+	}
+	for _, list := range lists {
+		listGV, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		if apiVersion != "" && listGV.Group != gv.Group {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !strings.EqualFold(r.Kind, kind) {
+				continue
+			}
+			version := listGV.Version
+			if gv.Version != "" {
+				version = gv.Version
+			}
+			return schema.GroupVersionResource{Group: listGV.Group, Version: version, Resource: r.Name}, r.Namespaced, nil
+		}
+	}
+	return schema.GroupVersionResource{}, false, fmt.Errorf("kind %s %s is not served by the cluster", apiVersion, kind)
+}
+
+// verifyResource returns the reason the resource fails the check, or an empty string if it passes
+func verifyResource(check canaryv1.KubernetesCheck, item unstructured.Unstructured) string {
+	if check.Ready {
+		if ready, reason := isResourceReady(item); !ready {
+			return reason
+		}
+	}
+
+	if check.MinReadyReplicas > 0 {
+		if replicas := readyReplicas(item); replicas < check.MinReadyReplicas {
+			return fmt.Sprintf("%d ready replicas, expecting at least %d", replicas, check.MinReadyReplicas)
+		}
+	}
+
+	for _, expected := range check.Conditions {
+		status := expected.Status
+		if status == "" {
+			status = "True"
+		}
+		condition := findCondition(item, expected.Type)
+		if condition == nil {
+			return fmt.Sprintf("condition %s not found", expected.Type)
+		}
+		if condition["status"] != status {
+			return fmt.Sprintf("%s=%v, expected %s: %v", expected.Type, condition["status"], status, condition["message"])
+		}
+	}
+
+	for _, expr := range check.JSONPath {
+		value, err := evaluateJSONPath(item, expr.Path)
+		if err != nil {
+			return fmt.Sprintf("invalid jsonPath %s: %v", expr.Path, err)
+		}
+		if expr.Value == "" && value == "" {
+			return fmt.Sprintf("%s is empty", expr.Path)
+		}
+		if expr.Value != "" && value != expr.Value {
+			return fmt.Sprintf("%s=%s, expected %s", expr.Path, value, expr.Value)
+		}
+	}
+	return ""
+}
+
+// isResourceReady applies the readiness rules of well known kinds, any other kind
+// is considered ready if it has a Ready condition with a status of True
+func isResourceReady(item unstructured.Unstructured) (bool, string) {
+	switch item.GetKind() {
+	case "Deployment", "ReplicaSet", "StatefulSet":
+		replicas, found, _ := unstructured.NestedInt64(item.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		observed, _, _ := unstructured.NestedInt64(item.Object, "status", "observedGeneration")
+		if observed < item.GetGeneration() {
+			return false, fmt.Sprintf("generation %d not yet observed", item.GetGeneration())
+		}
+		if ready := readyReplicas(item); ready < replicas {
+			return false, fmt.Sprintf("%d/%d replicas ready", ready, replicas)
+		}
+		return true, ""
+	case "DaemonSet":
+		desired, _, _ := unstructured.NestedInt64(item.Object, "status", "desiredNumberScheduled")
+		if ready := readyReplicas(item); ready < desired {
+			return false, fmt.Sprintf("%d/%d pods ready", ready, desired)
+		}
+		return true, ""
+	case "PersistentVolumeClaim", "PersistentVolume":
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		if phase != "Bound" {
+			return false, fmt.Sprintf("phase is %s", phase)
+		}
+		return true, ""
+	}
+
+	condition := findCondition(item, "Ready")
+	if condition == nil {
+		return false, "no Ready condition"
+	}
+	if condition["status"] != "True" {
+		return false, fmt.Sprintf("not ready: %v %v", condition["reason"], condition["message"])
+	}
+	return true, ""
+}
+
+func readyReplicas(item unstructured.Unstructured) int64 {
+	if item.GetKind() == "DaemonSet" {
+		ready, _, _ := unstructured.NestedInt64(item.Object, "status", "numberReady")
+		return ready
+	}
+	ready, _, _ := unstructured.NestedInt64(item.Object, "status", "readyReplicas")
+	return ready
+}
+
+func findCondition(item unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
+
+func evaluateJSONPath(item unstructured.Unstructured, path string) (string, error) {
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("check").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	if err := jp.Execute(buf, item.Object); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func resourceName(item unstructured.Unstructured) string {
+	if item.GetNamespace() == "" {
+		return item.GetName()
+	}
+	return item.GetNamespace() + "/" + item.GetName()
+}
@@ -1,7 +1,12 @@
 package checks
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
 
 	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/pkg"
@@ -17,23 +22,25 @@ func (c *LdapChecker) Type() string {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *LdapChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *LdapChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.LDAP {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // CheckConfig : Check every ldap entry for lookup and auth
 // Returns check result and metrics
-func (c *LdapChecker) Check(check v1.LDAPCheck) *pkg.CheckResult {
-	ld, err := ldap.DialURL(check.Host, ldap.DialWithTLSConfig(&tls.Config{
-		InsecureSkipVerify: check.SkipTLSVerify,
-	}))
+func (c *LdapChecker) Check(ctx context.Context, check v1.LDAPCheck) *pkg.CheckResult {
+	ld, err := dialLdap(ctx, check)
 	if err != nil {
 		return Failf(check, "Failed to connect %v", err)
 	}
+	defer ld.Close()
 
 	if err := ld.Bind(check.Username, check.Password); err != nil {
 		return Failf(check, "Failed to bind using %s %v", check.Username, err)
@@ -62,3 +69,62 @@ func (c *LdapChecker) Check(check v1.LDAPCheck) *pkg.CheckResult {
 		Duration: int64(timer.Elapsed()),
 	}
 }
+
+// dialLdap connects to the ldap, ldaps or ldapi url of check, the connection fails once ctx is done
+func dialLdap(ctx context.Context, check v1.LDAPCheck) (*ldap.Conn, error) {
+	u, err := url.Parse(check.Host)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "ldapi" {
+		path := u.Path
+		if path == "" || path == "/" {
+			path = "/var/run/slapd/ldapi"
+		}
+		conn, err := dialContext(ctx, "unix", path)
+		if err != nil {
+			return nil, err
+		}
+		return startLdap(ctx, conn, false), nil
+	}
+
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host, port = u.Host, ""
+	}
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = ldap.DefaultLdapPort
+		}
+		conn, err := dialContext(ctx, "tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			return nil, err
+		}
+		return startLdap(ctx, conn, false), nil
+	case "ldaps":
+		if port == "" {
+			port = ldap.DefaultLdapsPort
+		}
+		conn, err := dialContext(ctx, "tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: check.SkipTLSVerify})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return startLdap(ctx, tlsConn, true), nil
+	}
+	return nil, fmt.Errorf("unknown scheme %s", u.Scheme)
+}
+
+func startLdap(ctx context.Context, conn net.Conn, isTLS bool) *ldap.Conn {
+	ld := ldap.NewConn(conn, isTLS)
+	if deadline, ok := ctx.Deadline(); ok {
+		ld.SetTimeout(time.Until(deadline))
+	}
+	ld.Start()
+	return ld
+}
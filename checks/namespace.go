@@ -8,8 +8,6 @@ import (
 	"strings"
 	"time"
 
-	"k8s.io/api/extensions/v1beta1"
-
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"sigs.k8s.io/yaml"
@@ -20,7 +18,6 @@ import (
 	"github.com/flanksource/commons/logger"
 	perrors "github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"golang.org/x/sync/semaphore"
@@ -28,9 +25,10 @@ import (
 )
 
 type NamespaceChecker struct {
-	lock *semaphore.Weighted
-	k8s  *kubernetes.Clientset
-	ng   *NameGenerator
+	lock    *semaphore.Weighted
+	k8s     *kubernetes.Clientset
+	ng      *NameGenerator
+	ingress *ingressExposer
 }
 
 func NewNamespaceChecker() *NamespaceChecker {
@@ -44,25 +42,31 @@ func NewNamespaceChecker() *NamespaceChecker {
 		logger.Errorf("Failed to create kubernetes config %v", err)
 		return nc
 	}
+	dynamicClient, err := pkg.NewDynamicClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return nc
+	}
 
 	nc.k8s = k8sClient
+	nc.ingress = &ingressExposer{k8s: k8sClient, dynamic: dynamicClient}
 
 	return nc
 }
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *NamespaceChecker) Run(config canaryv1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *NamespaceChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newSerialCheckGroup(ctx, config)
 	for _, conf := range config.Namespace {
-		deadline := time.Now().Add(time.Duration(config.Interval) * time.Second)
-		if deadline.Before(time.Now().Add(time.Duration(conf.Deadline) * time.Millisecond)) {
-			deadline = time.Now().Add(time.Duration(conf.Deadline) * time.Millisecond)
-		}
-		results = append(results, c.Check(conf, deadline))
-
+		conf := conf
+		group.RunWithTimeout(conf, deadlineTimeout(config, conf, conf.Deadline), func(ctx context.Context) *pkg.CheckResult {
+			// the deadline only starts once the check is running
+			deadline, _ := ctx.Deadline()
+			return c.Check(conf, deadline)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // Type: returns checker type
@@ -238,10 +242,10 @@ func (c *NamespaceChecker) httpCheck(check canaryv1.NamespaceCheck, deadline tim
 	retryInterval := time.Duration(check.HttpRetryInterval) * time.Millisecond
 
 	for {
-		url := fmt.Sprintf("http://%s%s", check.IngressHost, check.Path)
+		url := ingressURL(check.IngressHost, check.Path, check.IngressTLSSecret)
 		logger.Debugf("Checking url %s", url)
 		httpTimer := NewTimer()
-		response, responseCode, err := c.getHttp(url, check.HttpTimeout, hardDeadline)
+		response, responseCode, err := c.getHttp(ingressHTTPClient(check.SkipTLSVerify), url, check.HttpTimeout, hardDeadline)
 		if err != nil && perrors.Is(err, context.DeadlineExceeded) {
 			if timer.Millis() > check.HttpTimeout && time.Now().Before(hardDeadline) {
 				logger.Debugf("[%s] request completed in %s, above threshold of %d", check, httpTimer, check.HttpTimeout)
@@ -323,60 +327,21 @@ func (c *NamespaceChecker) createServiceAndIngress(check canaryv1.NamespaceCheck
 		return perrors.Wrapf(err, "Failed to create service for pod %s in namespace %s", pod.Name, pod.Namespace)
 	}
 
-	ingress, err := c.k8s.ExtensionsV1beta1().Ingresses(ns.Name).Get(check.IngressName, metav1.GetOptions{})
-	if err != nil && !errors.IsNotFound(err) {
-		return perrors.Wrapf(err, "Failed to get ingress %s in namespace %s", check.IngressName, ns.Name)
-	} else if err == nil {
-		ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServiceName = svc.Name
-		ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServicePort = intstr.FromInt(int(check.Port))
-		if _, err := c.k8s.ExtensionsV1beta1().Ingresses(ns.Name).Update(ingress); err != nil {
-			return perrors.Wrapf(err, "failed to update ingress %s in namespace %s", check.IngressName, ns.Name)
-		}
-	} else {
-		ingress := c.newIngress(check, ns, svc.Name)
-		if _, err := c.k8s.ExtensionsV1beta1().Ingresses(ns.Name).Create(ingress); err != nil {
-			return perrors.Wrapf(err, "failed to create ingress %s in namespace %s", check.IngressName, ns.Name)
-		}
-	}
-
-	return nil
-}
-
-func (c *NamespaceChecker) newIngress(check canaryv1.NamespaceCheck, ns *v1.Namespace, svc string) *v1beta1.Ingress {
-	ingress := &v1beta1.Ingress{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Ingress",
-			APIVersion: "extensions/v1beta1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      check.IngressName,
-			Namespace: ns.Name,
-		},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
-				{
-					Host: check.IngressHost,
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
-								{
-									Path: check.Path,
-									Backend: v1beta1.IngressBackend{
-										ServiceName: svc,
-										ServicePort: intstr.FromInt(int(check.Port)),
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
+	route := ingressRoute{
+		Name:      check.IngressName,
+		Namespace: ns.Name,
+		Host:      check.IngressHost,
+		Path:      check.Path,
+		Service:   svc.Name,
+		Port:      check.Port,
+		Class:     check.IngressClass,
+		TLSSecret: check.IngressTLSSecret,
+		Gateway:   check.Gateway,
 	}
-	return ingress
+	return c.ingress.Expose(route)
 }
 
-func (c *NamespaceChecker) getHttp(url string, timeout int64, deadline time.Time) (string, int, error) {
+func (c *NamespaceChecker) getHttp(client *http.Client, url string, timeout int64, deadline time.Time) (string, int, error) {
 	var hardDeadline time.Time
 	softTimeoutDeadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
 	if softTimeoutDeadline.After(deadline) {
@@ -392,7 +357,7 @@ func (c *NamespaceChecker) getHttp(url string, timeout int64, deadline time.Time
 		return "", 0, perrors.Wrapf(err, "failed to create http request for url %s", url)
 	}
 
-	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return "", 0, perrors.Wrapf(err, "failed to get url %s", url)
 	}
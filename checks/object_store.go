@@ -0,0 +1,174 @@
+package checks
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// size of the random object uploaded by object store checks if not configured
+const defaultObjectSize = 16
+
+// storedObject describes an object listed from an object store
+type storedObject struct {
+	Name         string
+	Size         int64
+	LastModified time.Time
+}
+
+// objectStore is implemented by the object stores sharing the put/get/delete and freshness checks
+type objectStore interface {
+	// List returns every object in the container
+	List() ([]storedObject, error)
+	// Put uploads data, failing if the checksum computed by the store does not match
+	Put(name string, data []byte) error
+	Get(name string) ([]byte, error)
+	Delete(name string) error
+	Exists(name string) (bool, error)
+}
+
+// checkObjectStore lists the objects in the store, uploads a random object of size bytes,
+// downloads it back to compare and deletes it again
+func checkObjectStore(check pkg.GenericCheck, store objectStore, name string, size int64, labels map[string]string) *pkg.CheckResult {
+	timer := NewTimer()
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+	}
+
+	listTimer := NewTimer()
+	if _, err := store.List(); err != nil {
+		return Failf(check, "Failed to list objects: %v", err)
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "list_time", Type: metrics.HistogramType, Labels: labels, Value: listTimer.Elapsed()})
+
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return unexpectedErrorf(check, err, "failed to generate object")
+	}
+
+	uploadTimer := NewTimer()
+	if err := store.Put(name, data); err != nil {
+		return failWithMetrics(result, "Failed to put object %s: %v", name, err)
+	}
+	uploadTime := uploadTimer.Elapsed()
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "upload_time", Type: metrics.HistogramType, Labels: labels, Value: uploadTime})
+	if uploadTime > 0 {
+		result.Metrics = append(result.Metrics, pkg.Metric{Name: "upload_throughput", Type: metrics.GaugeType, Labels: labels, Value: megabytes(size) / (uploadTime / 1000)})
+	}
+
+	downloadTimer := NewTimer()
+	returned, err := store.Get(name)
+	if err != nil {
+		deleteStoredObject(store, name)
+		return failWithMetrics(result, "Failed to get object %s: %v", name, err)
+	}
+	downloadTime := downloadTimer.Elapsed()
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "download_time", Type: metrics.HistogramType, Labels: labels, Value: downloadTime})
+	if downloadTime > 0 {
+		result.Metrics = append(result.Metrics, pkg.Metric{Name: "download_throughput", Type: metrics.GaugeType, Labels: labels, Value: megabytes(int64(len(returned))) / (downloadTime / 1000)})
+	}
+	if !bytes.Equal(returned, data) {
+		deleteStoredObject(store, name)
+		return failWithMetrics(result, "Get object doesn't match: %d bytes with sha256 %x, expected %d bytes with sha256 %x", len(returned), sha256.Sum256(returned), len(data), sha256.Sum256(data))
+	}
+
+	deleteTimer := NewTimer()
+	if err := store.Delete(name); err != nil {
+		return failWithMetrics(result, "Failed to delete object %s: %v", name, err)
+	}
+	exists, err := store.Exists(name)
+	if err != nil {
+		return failWithMetrics(result, "Failed to confirm deletion of object %s: %v", name, err)
+	} else if exists {
+		return failWithMetrics(result, "Object %s still exists after deletion", name)
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "delete_time", Type: metrics.HistogramType, Labels: labels, Value: deleteTimer.Elapsed()})
+
+	result.Duration = int64(timer.Elapsed())
+	return result
+}
+
+func deleteStoredObject(store objectStore, name string) {
+	if err := store.Delete(name); err != nil {
+		logger.Errorf("Failed to delete object %s: %v", name, err)
+	}
+}
+
+// scanObjects checks that the most recent object matching objectPath is at most maxAge seconds old
+// and at least minSize bytes large
+func scanObjects(check pkg.GenericCheck, store objectStore, objectPath string, maxAge, minSize int64, labels map[string]string) *pkg.CheckResult {
+	var regex *regexp.Regexp
+	if objectPath != "" {
+		re, err := regexp.Compile(objectPath)
+		if err != nil {
+			return invalidErrorf(check, err, "failed to compile regex: %s", objectPath)
+		}
+		regex = re
+	}
+
+	objects, err := store.List()
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to list container")
+	}
+
+	var latestObject *storedObject
+	var count int
+	var totalSize int64
+	for i, obj := range objects {
+		if regex != nil && !regex.MatchString(obj.Name) {
+			continue
+		}
+		if latestObject == nil || obj.LastModified.After(latestObject.LastModified) {
+			latestObject = &objects[i]
+		}
+		count++
+		totalSize += obj.Size
+	}
+
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+		Metrics: []pkg.Metric{
+			{Name: "object_count", Type: metrics.GaugeType, Labels: labels, Value: float64(count)},
+			{Name: "total_size", Type: metrics.GaugeType, Labels: labels, Value: float64(totalSize)},
+		},
+	}
+	if latestObject == nil {
+		return failWithMetrics(result, "could not find any matching objects")
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "last_write", Type: metrics.GaugeType, Labels: labels, Value: float64(latestObject.LastModified.Unix())})
+
+	latestObjectAge := time.Since(latestObject.LastModified)
+	if latestObjectAge.Seconds() > float64(maxAge) {
+		return failWithMetrics(result, "Latest object age is %f seconds required at most %d seconds", latestObjectAge.Seconds(), maxAge)
+	}
+	if minSize > 0 && latestObject.Size < minSize {
+		return failWithMetrics(result, "Latest object is %d bytes required at least %d bytes", latestObject.Size, minSize)
+	}
+	result.Message = fmt.Sprintf("maxAge=%s size=%s objects=%d totalSize=%s", age(latestObjectAge), mb(latestObject.Size), count, mb(totalSize))
+	return result
+}
+
+func parseObjectSize(size string, defaultSize int64) (int64, error) {
+	if size == "" {
+		return defaultSize, nil
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, err
+	}
+	return quantity.Value(), nil
+}
+
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / 1024 / 1024
+}
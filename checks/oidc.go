@@ -0,0 +1,241 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+)
+
+var oidcKeyExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "canary_check_oidc_key_expiry",
+		Help: "The number of days until the certificate of an OIDC signing key expires",
+	},
+	[]string{"issuer", "kid"},
+)
+
+func init() {
+	prometheus.MustRegister(oidcKeyExpiry)
+}
+
+// oidcConfiguration is the subset of the OpenID Provider Metadata used by the check
+type oidcConfiguration struct {
+	Issuer        string `json:"issuer"`
+	TokenEndpoint string `json:"token_endpoint"`
+	JWKSURI       string `json:"jwks_uri"`
+}
+
+type OIDCChecker struct{}
+
+// Type: returns checker type
+func (c *OIDCChecker) Type() string {
+	return "oidc"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *OIDCChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.OIDC {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : Fetch the discovery document and signing keys of the issuer, request a token and verify it
+// Returns check result and metrics
+func (c *OIDCChecker) Check(ctx context.Context, check v1.OIDCCheck) *pkg.CheckResult {
+	if !check.ClientSecret.IsResolved() || !check.Password.IsResolved() {
+		return invalidErrorf(check, fmt.Errorf("clientSecret and password references are only resolved by the operator"), "invalid credentials")
+	}
+	timer := NewTimer()
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: check.SkipTLSVerify},
+		},
+	}
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+	}
+
+	discoveryTimer := NewTimer()
+	configuration := oidcConfiguration{}
+	if err := getJSON(ctx, client, strings.TrimSuffix(check.Issuer, "/")+"/.well-known/openid-configuration", &configuration); err != nil {
+		return Failf(check, "Failed to fetch discovery document: %v", err)
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "discovery_time", Type: metrics.HistogramType, Value: discoveryTimer.Elapsed()})
+	if configuration.Issuer != check.Issuer {
+		return failWithMetrics(result, "Discovery document issuer %s does not match %s", configuration.Issuer, check.Issuer)
+	}
+	if configuration.TokenEndpoint == "" || configuration.JWKSURI == "" {
+		return failWithMetrics(result, "Discovery document is missing token_endpoint or jwks_uri")
+	}
+
+	jwksTimer := NewTimer()
+	jwks := jose.JSONWebKeySet{}
+	if err := getJSON(ctx, client, configuration.JWKSURI, &jwks); err != nil {
+		return failWithMetrics(result, "Failed to fetch JWKS: %v", err)
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "jwks_time", Type: metrics.HistogramType, Value: jwksTimer.Elapsed()})
+	var signingKeys []jose.JSONWebKey
+	for _, key := range jwks.Keys {
+		if !key.Valid() {
+			return failWithMetrics(result, "Invalid key %s in JWKS", key.KeyID)
+		}
+		if key.Use == "" || key.Use == "sig" {
+			signingKeys = append(signingKeys, key)
+			if expiry, ok := keyExpiry(key); ok {
+				oidcKeyExpiry.WithLabelValues(check.Issuer, key.KeyID).Set(time.Until(expiry).Hours() / 24)
+			}
+		}
+	}
+	if len(signingKeys) == 0 {
+		return failWithMetrics(result, "JWKS does not contain any signing keys")
+	}
+
+	tokenTimer := NewTimer()
+	token, err := requestToken(ctx, client, check, configuration.TokenEndpoint)
+	if err != nil {
+		return failWithMetrics(result, "Failed to request token: %v", err)
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "token_time", Type: metrics.HistogramType, Value: tokenTimer.Elapsed()})
+
+	// the access token is not necessarily a JWT, the id token is used instead if it is returned
+	raw := token.AccessToken
+	if id, ok := token.Extra("id_token").(string); ok && id != "" {
+		if _, err := jwt.ParseSigned(raw); err != nil {
+			raw = id
+		}
+	}
+	signed, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return failWithMetrics(result, "Token is not a signed JWT: %v", err)
+	}
+	kid := signed.Headers[0].KeyID
+	signingKey := jwks.Key(kid)
+	if kid == "" && len(signingKeys) == 1 {
+		signingKey = signingKeys
+	}
+	if len(signingKey) == 0 {
+		return failWithMetrics(result, "Token is signed with key %s which is not published in the JWKS", kid)
+	}
+	claims := jwt.Claims{}
+	if err := signed.Claims(signingKey[0].Key, &claims); err != nil {
+		return failWithMetrics(result, "Invalid token signature: %v", err)
+	}
+	if claims.Expiry == nil {
+		return failWithMetrics(result, "Token does not expire")
+	}
+	expected := jwt.Expected{
+		Issuer:   check.Issuer,
+		Audience: jwt.Audience{check.ExpectedAudience()},
+		Time:     time.Now(),
+	}
+	if err := claims.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return failWithMetrics(result, "Invalid token claims: %v", err)
+	}
+
+	if expiry, ok := keyExpiry(signingKey[0]); ok && check.KeyExpiryDays > 0 {
+		cutoff := time.Now().Add(time.Duration(check.KeyExpiryDays) * 24 * time.Hour)
+		if expiry.Before(cutoff) && !hasReplacementKey(signingKeys, signingKey[0], cutoff) {
+			return failWithMetrics(result, "Signing key %s expires in %s and no replacement key is published", kid, age(time.Until(expiry)))
+		}
+	}
+
+	result.Duration = int64(timer.Elapsed())
+	result.Message = fmt.Sprintf("kid=%s keys=%d tokenExpiry=%s", kid, len(signingKeys), age(time.Until(claims.Expiry.Time())))
+	return result
+}
+
+func requestToken(ctx context.Context, client *http.Client, check v1.OIDCCheck, tokenURL string) (*oauth2.Token, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+	params := map[string][]string{}
+	if check.Audience != "" {
+		params["audience"] = []string{check.Audience}
+	}
+	switch check.GrantType {
+	case "", "client_credentials":
+		config := clientcredentials.Config{
+			ClientID:       check.ClientID,
+			ClientSecret:   check.ClientSecret.Value,
+			TokenURL:       tokenURL,
+			Scopes:         check.Scopes,
+			EndpointParams: params,
+		}
+		return config.Token(ctx)
+	case "password":
+		config := oauth2.Config{
+			ClientID:     check.ClientID,
+			ClientSecret: check.ClientSecret.Value,
+			Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
+			Scopes:       check.Scopes,
+		}
+		return config.PasswordCredentialsToken(ctx, check.Username, check.Password.Value)
+	}
+	return nil, fmt.Errorf("unsupported grant type %s", check.GrantType)
+}
+
+// keyExpiry returns the expiry of the leaf certificate of the key, if it has one
+func keyExpiry(key jose.JSONWebKey) (time.Time, bool) {
+	if len(key.Certificates) == 0 {
+		return time.Time{}, false
+	}
+	return key.Certificates[0].NotAfter, true
+}
+
+// hasReplacementKey returns true if another signing key of the same algorithm remains valid after cutoff
+func hasReplacementKey(keys []jose.JSONWebKey, current jose.JSONWebKey, cutoff time.Time) bool {
+	for _, key := range keys {
+		if key.KeyID == current.KeyID || (current.Algorithm != "" && key.Algorithm != "" && key.Algorithm != current.Algorithm) {
+			continue
+		}
+		if expiry, ok := keyExpiry(key); !ok || expiry.After(cutoff) {
+			return true
+		}
+	}
+	return false
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from %s: %s", url, resp.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid response from %s: %v", url, err)
+	}
+	return nil
+}
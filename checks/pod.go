@@ -9,8 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"k8s.io/api/extensions/v1beta1"
-
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	canaryv1 "github.com/flanksource/canary-checker/api/v1"
@@ -34,9 +32,10 @@ const (
 )
 
 type PodChecker struct {
-	lock *semaphore.Weighted
-	k8s  *kubernetes.Clientset
-	ng   *NameGenerator
+	lock    *semaphore.Weighted
+	k8s     *kubernetes.Clientset
+	ng      *NameGenerator
+	ingress *ingressExposer
 
 	latestNodeIndex int
 }
@@ -61,27 +60,31 @@ func NewPodChecker() *PodChecker {
 		logger.Errorf("Failed to create kubernetes config %v", err)
 		return pc
 	}
+	dynamicClient, err := pkg.NewDynamicClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return pc
+	}
 
 	pc.k8s = k8sClient
+	pc.ingress = &ingressExposer{k8s: k8sClient, dynamic: dynamicClient}
 
 	return pc
 }
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *PodChecker) Run(config canaryv1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *PodChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newSerialCheckGroup(ctx, config)
 	for _, conf := range config.Pod {
-		deadline := time.Now().Add(time.Duration(config.Interval) * time.Second)
-		if deadline.Before(time.Now().Add(time.Duration(conf.Deadline) * time.Millisecond)) {
-			deadline = time.Now().Add(time.Duration(conf.Deadline) * time.Millisecond)
-		}
-		result := c.Check(conf, deadline)
-		if result != nil {
-			results = append(results, result)
-		}
-	}
-	return results
+		conf := conf
+		group.RunWithTimeout(conf, deadlineTimeout(config, conf, conf.Deadline), func(ctx context.Context) *pkg.CheckResult {
+			// the deadline only starts once the check is running
+			deadline, _ := ctx.Deadline()
+			return c.Check(conf, deadline)
+		})
+	}
+	return group.Wait()
 }
 
 // Type: returns checker type
@@ -288,12 +291,12 @@ func (c *PodChecker) httpCheck(podCheck canaryv1.PodCheck, deadline time.Time) (
 	retryInterval := time.Duration(podCheck.HttpRetryInterval) * time.Millisecond
 
 	for {
-		url := fmt.Sprintf("http://%s%s", podCheck.IngressHost, podCheck.Path)
+		url := ingressURL(podCheck.IngressHost, podCheck.Path, podCheck.IngressTLSSecret)
 		if _, err := http.NewRequest("GET", url, nil); err != nil {
 			return 0, 0, Failf(podCheck, "invalid url: %v", err)
 		}
 		httpTimer := NewTimer()
-		response, responseCode, err := c.getHttp(url, podCheck.HttpTimeout, hardDeadline)
+		response, responseCode, err := c.getHttp(ingressHTTPClient(podCheck.SkipTLSVerify), url, podCheck.HttpTimeout, hardDeadline)
 		if err != nil && perrors.Is(err, context.DeadlineExceeded) {
 			if timer.Millis() > podCheck.HttpTimeout && time.Now().Before(hardDeadline) {
 				logger.Debugf("[%s] request completed in %s, above threshold of %d", podCheck, httpTimer, podCheck.HttpTimeout)
@@ -373,60 +376,21 @@ func (c *PodChecker) createServiceAndIngress(podCheck canaryv1.PodCheck, pod *v1
 		return perrors.Wrapf(err, "Failed to create service for pod %s in namespace %s", pod.Name, pod.Namespace)
 	}
 
-	ingress, err := c.k8s.ExtensionsV1beta1().Ingresses(podCheck.Namespace).Get(podCheck.IngressName, metav1.GetOptions{})
-	if err != nil && !errors.IsNotFound(err) {
-		return perrors.Wrapf(err, "Failed to get ingress %s in namespace %s", podCheck.IngressName, podCheck.Namespace)
-	} else if err == nil {
-		ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServiceName = svc.Name
-		ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServicePort = intstr.FromInt(int(podCheck.Port))
-		if _, err := c.k8s.ExtensionsV1beta1().Ingresses(podCheck.Namespace).Update(ingress); err != nil {
-			return perrors.Wrapf(err, "failed to update ingress %s in namespace %s", podCheck.IngressName, podCheck.Namespace)
-		}
-	} else {
-		ingress := c.newIngress(podCheck, svc.Name)
-		if _, err := c.k8s.ExtensionsV1beta1().Ingresses(podCheck.Namespace).Create(ingress); err != nil {
-			return perrors.Wrapf(err, "failed to create ingress %s in namespace %s", podCheck.IngressName, podCheck.Namespace)
-		}
-	}
-
-	return nil
-}
-
-func (c *PodChecker) newIngress(podCheck canaryv1.PodCheck, svc string) *v1beta1.Ingress {
-	ingress := &v1beta1.Ingress{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Ingress",
-			APIVersion: "extensions/v1beta1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podCheck.IngressName,
-			Namespace: podCheck.Namespace,
-		},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
-				{
-					Host: podCheck.IngressHost,
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
-								{
-									Path: podCheck.Path,
-									Backend: v1beta1.IngressBackend{
-										ServiceName: svc,
-										ServicePort: intstr.FromInt(int(podCheck.Port)),
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
+	route := ingressRoute{
+		Name:      podCheck.IngressName,
+		Namespace: podCheck.Namespace,
+		Host:      podCheck.IngressHost,
+		Path:      podCheck.Path,
+		Service:   svc.Name,
+		Port:      podCheck.Port,
+		Class:     podCheck.IngressClass,
+		TLSSecret: podCheck.IngressTLSSecret,
+		Gateway:   podCheck.Gateway,
 	}
-	return ingress
+	return c.ingress.Expose(route)
 }
 
-func (c *PodChecker) getHttp(url string, timeout int64, deadline time.Time) (string, int, error) {
+func (c *PodChecker) getHttp(client *http.Client, url string, timeout int64, deadline time.Time) (string, int, error) {
 	var hardDeadline time.Time
 	softTimeoutDeadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
 	if softTimeoutDeadline.After(deadline) {
@@ -442,7 +406,7 @@ func (c *PodChecker) getHttp(url string, timeout int64, deadline time.Time) (str
 		return "", 0, perrors.Wrapf(err, "failed to create http request for url %s", url)
 	}
 
-	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return "", 0, perrors.Wrapf(err, "failed to get url %s", url)
 	}
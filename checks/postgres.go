@@ -1,6 +1,7 @@
 package checks
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -23,25 +24,27 @@ func (c *PostgresChecker) Type() string {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *PostgresChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *PostgresChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.Postgres {
-		results = append(results, c.Check(conf)...)
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // CheckConfig : Attempts to connect to a DB using the specified
 //               driver and connection string
 // Returns check result and metrics
-func (c *PostgresChecker) Check(check v1.PostgresCheck) []*pkg.CheckResult {
-	var result []*pkg.CheckResult
-
+func (c *PostgresChecker) Check(ctx context.Context, check v1.PostgresCheck) *pkg.CheckResult {
 	start := time.Now()
-	queryResult, err := connectWithDriver(check.Driver, check.Connection, check.Query)
+	queryResult, err := connectWithDriver(ctx, check.Driver, check.Connection, check.Query)
 	elapsed := time.Since(start)
 	if (err != nil) || (queryResult != check.Result) {
 		checkResult := &pkg.CheckResult{
+			Check:    check,
 			Pass:     false,
 			Invalid:  false,
 			Duration: elapsed.Milliseconds(),
@@ -53,8 +56,7 @@ func (c *PostgresChecker) Check(check v1.PostgresCheck) []*pkg.CheckResult {
 		if queryResult != check.Result {
 			logger.Errorf("Query '%s', did not return '%d', but '%d'", check.Query, check.Result, queryResult)
 		}
-		result = append(result, checkResult)
-		return result
+		return checkResult
 	}
 
 	checkResult := &pkg.CheckResult{
@@ -64,16 +66,15 @@ func (c *PostgresChecker) Check(check v1.PostgresCheck) []*pkg.CheckResult {
 		Duration: elapsed.Milliseconds(),
 		Metrics:  []pkg.Metric{},
 	}
-	result = append(result, checkResult)
 	logger.Debugf("Duration %f", float64(elapsed.Milliseconds()))
-	return result
+	return checkResult
 
 }
 
 // Connects to a db using the specified `driver` and `connectionstring`
 // Performs the test query given in `query`.
 // Gives the single row test query result as result.
-func connectWithDriver(driver string, connectionSting string, query string) (int, error) {
+func connectWithDriver(ctx context.Context, driver string, connectionSting string, query string) (int, error) {
 	db, err := sql.Open(driver, connectionSting)
 	if err != nil {
 		logger.Errorf(err.Error())
@@ -82,7 +83,7 @@ func connectWithDriver(driver string, connectionSting string, query string) (int
 	defer db.Close()
 
 	var resultValue int
-	err = db.QueryRow(query).Scan(&resultValue)
+	err = db.QueryRowContext(ctx, query).Scan(&resultValue)
 	if err != nil {
 		logger.Errorf(err.Error())
 		return 0, err
@@ -0,0 +1,169 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+type PrometheusChecker struct{}
+
+// prometheusTransports are shared by the prometheus checks, depending on whether they skip TLS verification
+var prometheusTransports = map[bool]http.RoundTripper{
+	false: &http.Transport{},
+	true:  &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+}
+
+// Type: returns checker type
+func (c *PrometheusChecker) Type() string {
+	return "prometheus"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *PrometheusChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.Prometheus {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : Run an instant query and verify the returned vector
+// Returns check result and metrics
+func (c *PrometheusChecker) Check(ctx context.Context, check v1.PrometheusCheck) *pkg.CheckResult {
+	compare, err := promComparison(check.Operator, check.Value)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid comparison")
+	}
+
+	transport := prometheusTransports[check.SkipTLSVerify]
+	if check.Username != "" {
+		if !check.Password.IsResolved() {
+			return invalidErrorf(check, fmt.Errorf("password references are only resolved by the operator"), "invalid credentials")
+		}
+		transport = basicAuthRoundTripper{username: check.Username, password: check.Password.Value, next: transport}
+	}
+	client, err := api.NewClient(api.Config{Address: check.Host, RoundTripper: transport})
+	if err != nil {
+		return invalidErrorf(check, err, "invalid prometheus host")
+	}
+
+	timer := NewTimer()
+	value, warnings, err := promv1.NewAPI(client).Query(ctx, check.Query, time.Now())
+	if err != nil {
+		return Failf(check, "failed to query %s: %v", check.Host, err)
+	}
+	duration := int64(timer.Elapsed())
+
+	var samples model.Vector
+	switch v := value.(type) {
+	case model.Vector:
+		samples = v
+	case *model.Scalar:
+		samples = model.Vector{&model.Sample{Value: v.Value, Timestamp: v.Timestamp}}
+	default:
+		return invalidErrorf(check, fmt.Errorf("unsupported result type %s", value.Type()), "query must return a vector or scalar")
+	}
+
+	result := &pkg.CheckResult{
+		Check:    check,
+		Pass:     true,
+		Duration: duration,
+		Metrics: []pkg.Metric{
+			{
+				Name:   "series_count",
+				Type:   metrics.GaugeType,
+				Labels: map[string]string{"check": pkg.CheckName(check)},
+				Value:  float64(len(samples)),
+			},
+		},
+	}
+	if len(warnings) > 0 {
+		result.Message = fmt.Sprintf("warnings: %s", strings.Join(warnings, ", "))
+	}
+
+	if check.MustBeEmpty {
+		if len(samples) > 0 {
+			return failWithMetrics(result, "expected no series, got %d: %s", len(samples), describeSeries(samples))
+		}
+		return result
+	}
+
+	if len(samples) < check.MinSeries {
+		return failWithMetrics(result, "returned %d series, expecting at least %d", len(samples), check.MinSeries)
+	}
+
+	if compare != nil {
+		var failed model.Vector
+		for _, sample := range samples {
+			if !compare(float64(sample.Value)) {
+				failed = append(failed, sample)
+			}
+		}
+		if len(failed) > 0 {
+			return failWithMetrics(result, "%d series not %s %s: %s", len(failed), check.Operator, check.Value, describeSeries(failed))
+		}
+	}
+	return result
+}
+
+// promComparison returns a function comparing a sample against value,
+// or nil if no operator is configured
+func promComparison(operator, value string) (func(float64) bool, error) {
+	if operator == "" {
+		return nil, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("value %s is not a number", value)
+	}
+	switch operator {
+	case ">":
+		return func(v float64) bool { return v > threshold }, nil
+	case ">=":
+		return func(v float64) bool { return v >= threshold }, nil
+	case "<":
+		return func(v float64) bool { return v < threshold }, nil
+	case "<=":
+		return func(v float64) bool { return v <= threshold }, nil
+	case "==":
+		return func(v float64) bool { return v == threshold }, nil
+	case "!=":
+		return func(v float64) bool { return v != threshold }, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", operator)
+}
+
+func describeSeries(samples model.Vector) string {
+	var series []string
+	for _, sample := range samples {
+		series = append(series, fmt.Sprintf("%s=%s", sample.Metric, sample.Value))
+	}
+	return summarize(series)
+}
+
+type basicAuthRoundTripper struct {
+	username, password string
+	next               http.RoundTripper
+}
+
+func (rt basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(rt.username, rt.password)
+	return rt.next.RoundTrip(req)
+}
@@ -0,0 +1,379 @@
+package checks
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	perrors "github.com/pkg/errors"
+)
+
+const (
+	defaultRegistry     = "registry-1.docker.io"
+	registryHTTPTimeout = 5 * time.Minute
+
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+)
+
+// imageReference is a parsed image name, e.g. docker.io/library/busybox:latest
+type imageReference struct {
+	Registry   string
+	Repository string
+	// Reference is either a tag or a digest
+	Reference string
+}
+
+func (r imageReference) String() string {
+	if strings.HasPrefix(r.Reference, "sha256:") {
+		return fmt.Sprintf("%s/%s@%s", r.Registry, r.Repository, r.Reference)
+	}
+	return fmt.Sprintf("%s/%s:%s", r.Registry, r.Repository, r.Reference)
+}
+
+// parseImageReference parses an image name using the same defaults as docker pull
+func parseImageReference(image string) (imageReference, error) {
+	if image == "" {
+		return imageReference{}, fmt.Errorf("image cannot be empty")
+	}
+	ref := imageReference{Registry: defaultRegistry, Reference: "latest"}
+	name := image
+	if i := strings.Index(name, "@"); i > 0 {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		name = parts[1]
+	}
+	if ref.Registry == "docker.io" || ref.Registry == "index.docker.io" {
+		ref.Registry = defaultRegistry
+	}
+	if ref.Registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+	return ref, nil
+}
+
+// manifest covers the fields of docker and OCI image manifests and indexes used by the checks
+type manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        *descriptor  `json:"config,omitempty"`
+	Layers        []descriptor `json:"layers,omitempty"`
+	Manifests     []descriptor `json:"manifests,omitempty"`
+}
+
+type descriptor struct {
+	MediaType string    `json:"mediaType"`
+	Size      int64     `json:"size"`
+	Digest    string    `json:"digest"`
+	Platform  *platform `json:"platform,omitempty"`
+}
+
+type platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+func (m manifest) isIndex() bool {
+	return m.MediaType == mediaTypeDockerManifestList || m.MediaType == mediaTypeOCIIndex || len(m.Manifests) > 0
+}
+
+// registryClient is a minimal client of the OCI distribution API, it handles
+// both basic and bearer token authentication
+type registryClient struct {
+	ctx      context.Context
+	ref      imageReference
+	username string
+	password string
+	scheme   string
+	client   *http.Client
+	token    string
+}
+
+// newRegistryClient returns a client for the repository of ref, its requests are cancelled once ctx is done
+func newRegistryClient(ctx context.Context, ref imageReference, username, password string, skipTLSVerify, plainHTTP bool) *registryClient {
+	scheme := "https"
+	if plainHTTP {
+		scheme = "http"
+	}
+	return &registryClient{
+		ctx:      ctx,
+		ref:      ref,
+		username: username,
+		password: password,
+		scheme:   scheme,
+		client: &http.Client{
+			Timeout: registryHTTPTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: skipTLSVerify},
+			},
+		},
+	}
+}
+
+func (r *registryClient) url(path string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/%s", r.scheme, r.ref.Registry, r.ref.Repository, path)
+}
+
+// do sends the request, authenticating and retrying it once if the registry responds with a challenge
+func (r *registryClient) do(method, url string, body []byte, headers map[string]string) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = strings.NewReader(string(body))
+		}
+		req, err := http.NewRequest(method, url, reader)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		} else if r.username != "" {
+			req.SetBasicAuth(r.username, r.password)
+		}
+		return r.client.Do(req.WithContext(r.ctx))
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("%s %s: unauthorized", method, url)
+	}
+	if err := r.authenticate(challenge); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+// authenticate requests a bearer token from the realm of the challenge
+func (r *registryClient) authenticate(challenge string) error {
+	params := parseChallenge(challenge[len("bearer "):])
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("invalid authentication challenge %s", challenge)
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", r.ref.Repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	resp, err := r.client.Do(req.WithContext(r.ctx))
+	if err != nil {
+		return perrors.Wrapf(err, "failed to get token from %s", realm.Host)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get token from %s: %s", realm.Host, resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return perrors.Wrapf(err, "invalid token response from %s", realm.Host)
+	}
+	r.token = token.Token
+	if r.token == "" {
+		r.token = token.AccessToken
+	}
+	return nil
+}
+
+// parseChallenge parses the comma separated key="value" pairs of a WWW-Authenticate header
+func parseChallenge(s string) map[string]string {
+	params := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, ", ")
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else if comma := strings.Index(s, ","); comma >= 0 {
+			value, s = s[:comma], s[comma:]
+		} else {
+			value, s = s, ""
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// Manifest fetches the manifest of reference, returning it together with its digest
+func (r *registryClient) Manifest(reference string) (*manifest, string, error) {
+	accept := strings.Join([]string{mediaTypeDockerManifest, mediaTypeDockerManifestList, mediaTypeOCIManifest, mediaTypeOCIIndex}, ", ")
+	resp, err := r.do("GET", r.url("manifests/"+reference), nil, map[string]string{"Accept": accept})
+	if err != nil {
+		return nil, "", perrors.Wrapf(err, "failed to get manifest %s", reference)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", perrors.Wrapf(err, "failed to read manifest %s", reference)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to get manifest %s: %s", reference, resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = sha256Digest(body)
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, "", perrors.Wrapf(err, "invalid manifest %s", reference)
+	}
+	if m.MediaType == "" {
+		m.MediaType = resp.Header.Get("Content-Type")
+	}
+	return m, digest, nil
+}
+
+// Blob downloads the blob and verifies its digest, returning the number of bytes read
+func (r *registryClient) Blob(digest string) (int64, error) {
+	resp, err := r.do("GET", r.url("blobs/"+digest), nil, nil)
+	if err != nil {
+		return 0, perrors.Wrapf(err, "failed to get blob %s", digest)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to get blob %s: %s", digest, resp.Status)
+	}
+	hash := sha256.New()
+	n, err := io.Copy(hash, resp.Body)
+	if err != nil {
+		return n, perrors.Wrapf(err, "failed to read blob %s", digest)
+	}
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); strings.HasPrefix(digest, "sha256:") && actual != digest {
+		return n, fmt.Errorf("blob digest mismatch %s != %s", actual, digest)
+	}
+	return n, nil
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// BlobExists returns true if the registry already has the blob
+func (r *registryClient) BlobExists(digest string) (bool, error) {
+	resp, err := r.do("HEAD", r.url("blobs/"+digest), nil, nil)
+	if err != nil {
+		return false, perrors.Wrapf(err, "failed to check blob %s", digest)
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// UploadBlob uploads data as a single monolithic upload, returning its digest
+func (r *registryClient) UploadBlob(data []byte) (string, error) {
+	digest := sha256Digest(data)
+	if exists, err := r.BlobExists(digest); err != nil || exists {
+		return digest, err
+	}
+
+	resp, err := r.do("POST", r.url("blobs/uploads/"), nil, nil)
+	if err != nil {
+		return "", perrors.Wrap(err, "failed to start blob upload")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("failed to start blob upload: %s", resp.Status)
+	}
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", perrors.Wrap(err, "invalid blob upload location")
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = r.do("PUT", location.String(), data, map[string]string{"Content-Type": "application/octet-stream"})
+	if err != nil {
+		return "", perrors.Wrapf(err, "failed to upload blob %s", digest)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to upload blob %s: %s", digest, resp.Status)
+	}
+	return digest, nil
+}
+
+// PutManifest uploads the manifest under reference, returning its digest
+func (r *registryClient) PutManifest(reference, mediaType string, body []byte) (string, error) {
+	resp, err := r.do("PUT", r.url("manifests/"+reference), body, map[string]string{"Content-Type": mediaType})
+	if err != nil {
+		return "", perrors.Wrapf(err, "failed to put manifest %s", reference)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to put manifest %s: %s", reference, resp.Status)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = sha256Digest(body)
+	}
+	return digest, nil
+}
+
+// DeleteManifest deletes the manifest by digest, removing every tag referencing it
+func (r *registryClient) DeleteManifest(digest string) error {
+	resp, err := r.do("DELETE", r.url("manifests/"+digest), nil, nil)
+	if err != nil {
+		return perrors.Wrapf(err, "failed to delete manifest %s", digest)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete manifest %s: %s", digest, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/maintenance"
+	"github.com/flanksource/commons/logger"
+)
+
+// MaxConcurrency limits the number of checks running at the same time across all canaries, 0 for no limit
+var MaxConcurrency = 20
+
+// MaxConcurrencyPerType limits the number of checks of the same type running at the same time, 0 for no limit
+var MaxConcurrencyPerType = 10
+
+// Retries is the default number of times a failed check is retried, unless the canary specifies it
+var Retries = 0
+
+// RetryBackoff is the delay before the first retry of a failed check, which doubles with every retry
+var RetryBackoff = time.Second
+
+var limits = struct {
+	sync.Mutex
+	global chan struct{}
+	types  map[string]chan struct{}
+}{types: make(map[string]chan struct{})}
+
+// semaphores returns the global and per type semaphores limiting checks of checkType, which are nil
+// if there is no limit
+func semaphores(checkType string) (global chan struct{}, perType chan struct{}) {
+	limits.Lock()
+	defer limits.Unlock()
+	if limits.global == nil && MaxConcurrency > 0 {
+		limits.global = make(chan struct{}, MaxConcurrency)
+	}
+	if _, found := limits.types[checkType]; !found && MaxConcurrencyPerType > 0 {
+		limits.types[checkType] = make(chan struct{}, MaxConcurrencyPerType)
+	}
+	return limits.global, limits.types[checkType]
+}
+
+func acquireSlot(ctx context.Context, sem chan struct{}) error {
+	if sem == nil {
+		return nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func releaseSlot(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+// RunChecks runs the checks of all checkers in config concurrently, returning their results in the
+// order of the checkers and of the checks in config. Checks wait for the checks they depend on and checks
+// within a maintenance window or silence are marked as in maintenance, canary is the namespace/name of
+// the canary the checks belong to or empty
+func RunChecks(ctx context.Context, canary string, checkers []Checker, config v1.CanarySpec) []*pkg.CheckResult {
+	results := make([][]*pkg.CheckResult, len(checkers))
+	deps := newDependencies(canary, len(checkers))
+	wg := sync.WaitGroup{}
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			run := &checkerRun{deps: deps}
+			// checkers that do not wait for their checks still need to report they submitted them all
+			defer run.submitted()
+			results[i] = checker.Run(context.WithValue(ctx, checkerRunKey{}, run), config)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	var all []*pkg.CheckResult
+	for _, checkerResults := range results {
+		for _, result := range checkerResults {
+			markMaintenance(canary, config, result)
+		}
+		all = append(all, checkerResults...)
+	}
+	return all
+}
+
+// markMaintenance marks result as in maintenance if its check was in maintenance when it started
+func markMaintenance(canary string, config v1.CanarySpec, result *pkg.CheckResult) {
+	if result == nil || result.Check == nil {
+		return
+	}
+	start := result.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	if reason, active := maintenance.Active(canary, config, result.Check, start); active {
+		result.Maintenance = true
+		result.Message = fmt.Sprintf("%s (maintenance: %s)", result.Message, reason)
+	}
+}
+
+// checkGroup runs the checks of a checker within the concurrency limits once their dependencies have
+// completed, keeping their results in the order the checks were added
+type checkGroup struct {
+	ctx     context.Context
+	config  v1.CanarySpec
+	run     *checkerRun
+	serial  chan struct{}
+	wg      sync.WaitGroup
+	mtx     sync.Mutex
+	results []*pkg.CheckResult
+}
+
+func newCheckGroup(ctx context.Context, config v1.CanarySpec) *checkGroup {
+	return &checkGroup{ctx: ctx, config: config, run: checkerRunFrom(ctx)}
+}
+
+// newSerialCheckGroup returns a group running one check at a time, for checkers that skip a check
+// while another one is in progress
+func newSerialCheckGroup(ctx context.Context, config v1.CanarySpec) *checkGroup {
+	group := newCheckGroup(ctx, config)
+	group.serial = make(chan struct{}, 1)
+	return group
+}
+
+// Run runs fn with the timeout of check, see RunWithTimeout
+func (g *checkGroup) Run(check timeoutCheck, fn func(ctx context.Context) *pkg.CheckResult) {
+	g.RunWithTimeout(check, checkTimeout(g.config, check), fn)
+}
+
+// RunWithTimeout runs fn once a slot is available, the timeout only starts once fn is running
+func (g *checkGroup) RunWithTimeout(check pkg.GenericCheck, timeout time.Duration, fn func(ctx context.Context) *pkg.CheckResult) {
+	g.mtx.Lock()
+	i := len(g.results)
+	g.results = append(g.results, nil)
+	g.mtx.Unlock()
+
+	g.run.register(check)
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		result := g.run.wait(g.ctx, check)
+		if result == nil {
+			if g.serial != nil {
+				g.serial <- struct{}{}
+			}
+			running := &sync.WaitGroup{}
+			result = runRetried(g.ctx, check, timeout, g.retries(), running, fn)
+			if g.serial != nil {
+				// the next check only starts once the attempts that timed out have returned
+				go func() {
+					running.Wait()
+					<-g.serial
+				}()
+			}
+		}
+		g.run.complete(check, result)
+		g.mtx.Lock()
+		g.results[i] = result
+		g.mtx.Unlock()
+	}()
+}
+
+func (g *checkGroup) retries() int {
+	if g.config.Retries > 0 {
+		return g.config.Retries
+	}
+	return Retries
+}
+
+// Wait waits for all checks of the group to complete and returns their results, leaving out the
+// checks that were skipped
+func (g *checkGroup) Wait() []*pkg.CheckResult {
+	g.run.submitted()
+	g.wg.Wait()
+	var results []*pkg.CheckResult
+	for _, result := range g.results {
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// runRetried runs fn with runLimited, retrying a failed check with an exponential backoff. Invalid
+// checks and skipped checks are not retried
+func runRetried(ctx context.Context, check pkg.GenericCheck, timeout time.Duration, retries int, running *sync.WaitGroup, fn func(ctx context.Context) *pkg.CheckResult) *pkg.CheckResult {
+	backoff := RetryBackoff
+	var start time.Time
+	for attempt := 0; ; attempt++ {
+		result := runLimited(ctx, check, timeout, running, fn)
+		if attempt == 0 && result != nil {
+			start = result.Start
+		}
+		if result == nil || result.Pass || result.Invalid || attempt >= retries {
+			if result != nil {
+				result.Start = start
+			}
+			return result
+		}
+		logger.Debugf("[%s] %s failed, retrying in %s: %s", check.GetType(), check.GetEndpoint(), backoff, result.Message)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return result
+		}
+		backoff *= 2
+	}
+}
+
+// runLimited runs fn with runWithTimeout while holding the global and per type semaphores, recording
+// when the check started and ended. The semaphores are held until fn returns, even if it timed out, and
+// running is done once fn returns
+func runLimited(ctx context.Context, check pkg.GenericCheck, timeout time.Duration, running *sync.WaitGroup, fn func(ctx context.Context) *pkg.CheckResult) *pkg.CheckResult {
+	global, perType := semaphores(check.GetType())
+	if err := acquireSlot(ctx, perType); err != nil {
+		return Failf(check, "cancelled: %v", err)
+	}
+	if err := acquireSlot(ctx, global); err != nil {
+		releaseSlot(perType)
+		return Failf(check, "cancelled: %v", err)
+	}
+	running.Add(1)
+	finished := func() {
+		releaseSlot(global)
+		releaseSlot(perType)
+		running.Done()
+	}
+
+	start := time.Now()
+	result := runWithTimeout(ctx, check, timeout, fn, finished)
+	end := time.Now()
+	if result != nil {
+		result.Start = start
+		result.End = end
+		if result.Duration == 0 {
+			result.Duration = end.Sub(start).Milliseconds()
+		}
+	}
+	return result
+}
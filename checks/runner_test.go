@@ -0,0 +1,147 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+)
+
+// concurrency tracks the peak number of checks running at the same time, in total and by type
+type concurrency struct {
+	mtx     sync.Mutex
+	running map[string]int
+	peak    map[string]int
+}
+
+func (c *concurrency) start(checkType string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, key := range []string{"", checkType} {
+		c.running[key]++
+		if c.running[key] > c.peak[key] {
+			c.peak[key] = c.running[key]
+		}
+	}
+}
+
+func (c *concurrency) end(checkType string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.running[""]--
+	c.running[checkType]--
+}
+
+// sleepingChecker runs the checks returned by checks, each of which sleeps for the duration of its name
+type sleepingChecker struct {
+	checkType   string
+	checks      func(config v1.CanarySpec) []pkg.GenericCheck
+	durations   map[string]time.Duration
+	concurrency *concurrency
+}
+
+func (c *sleepingChecker) Type() string {
+	return c.checkType
+}
+
+func (c *sleepingChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, check := range c.checks(config) {
+		check := check
+		group.RunWithTimeout(check, 0, func(ctx context.Context) *pkg.CheckResult {
+			c.concurrency.start(check.GetType())
+			defer c.concurrency.end(check.GetType())
+			time.Sleep(c.durations[check.GetName()])
+			return Passf(check, "passed")
+		})
+	}
+	return group.Wait()
+}
+
+// resetLimits recreates the semaphores with the given limits
+func resetLimits(maxConcurrency, maxConcurrencyPerType int) {
+	limits.Lock()
+	defer limits.Unlock()
+	MaxConcurrency = maxConcurrency
+	MaxConcurrencyPerType = maxConcurrencyPerType
+	limits.global = nil
+	limits.types = make(map[string]chan struct{})
+}
+
+func TestRunChecksConcurrency(t *testing.T) {
+	defer resetLimits(MaxConcurrency, MaxConcurrencyPerType)
+	tests := []struct {
+		name                  string
+		maxConcurrency        int
+		maxConcurrencyPerType int
+	}{
+		{name: "global limit", maxConcurrency: 3, maxConcurrencyPerType: 0},
+		{name: "per type limit", maxConcurrency: 0, maxConcurrencyPerType: 2},
+		{name: "both limits", maxConcurrency: 3, maxConcurrencyPerType: 2},
+		{name: "no limits", maxConcurrency: 0, maxConcurrencyPerType: 0},
+	}
+	for _, tc := range tests {
+		resetLimits(tc.maxConcurrency, tc.maxConcurrencyPerType)
+		config := v1.CanarySpec{}
+		durations := make(map[string]time.Duration)
+		var want []string
+		for i := 0; i < 6; i++ {
+			// earlier checks take longer, so they complete in the reverse order
+			http := fmt.Sprintf("http-%d", i)
+			dns := fmt.Sprintf("dns-%d", i)
+			durations[http] = time.Duration(6-i) * 10 * time.Millisecond
+			durations[dns] = time.Duration(6-i) * 10 * time.Millisecond
+			config.HTTP = append(config.HTTP, v1.HTTPCheck{Name: http, Endpoint: "http://" + http})
+			config.DNS = append(config.DNS, v1.DNSCheck{Name: dns, Query: dns})
+			want = append(want, http)
+		}
+		for i := 0; i < 6; i++ {
+			want = append(want, fmt.Sprintf("dns-%d", i))
+		}
+		counts := &concurrency{running: make(map[string]int), peak: make(map[string]int)}
+		checkers := []Checker{
+			&sleepingChecker{checkType: "http", durations: durations, concurrency: counts, checks: func(config v1.CanarySpec) []pkg.GenericCheck {
+				var checks []pkg.GenericCheck
+				for _, check := range config.HTTP {
+					checks = append(checks, check)
+				}
+				return checks
+			}},
+			&sleepingChecker{checkType: "dns", durations: durations, concurrency: counts, checks: func(config v1.CanarySpec) []pkg.GenericCheck {
+				var checks []pkg.GenericCheck
+				for _, check := range config.DNS {
+					checks = append(checks, check)
+				}
+				return checks
+			}},
+		}
+
+		results := RunChecks(context.Background(), "", checkers, config)
+
+		if tc.maxConcurrency > 0 && counts.peak[""] > tc.maxConcurrency {
+			t.Errorf("Test %s failed. Expected at most %d checks at the same time, but found %d", tc.name, tc.maxConcurrency, counts.peak[""])
+		}
+		for _, checkType := range []string{"http", "dns"} {
+			if tc.maxConcurrencyPerType > 0 && counts.peak[checkType] > tc.maxConcurrencyPerType {
+				t.Errorf("Test %s failed. Expected at most %d %s checks at the same time, but found %d", tc.name, tc.maxConcurrencyPerType, checkType, counts.peak[checkType])
+			}
+		}
+		if counts.peak[""] < 2 {
+			t.Errorf("Test %s failed. Expected checks to run concurrently, but found at most %d at the same time", tc.name, counts.peak[""])
+		}
+
+		if len(results) != len(want) {
+			t.Errorf("Test %s failed. Expected %d results, but found %d", tc.name, len(want), len(results))
+			continue
+		}
+		for i, result := range results {
+			if name := result.Check.GetName(); name != want[i] {
+				t.Errorf("Test %s failed. Expected result %d to be %s, but found %s", tc.name, i, want[i], name)
+			}
+		}
+	}
+}
@@ -2,19 +2,34 @@ package checks
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
-	"io/ioutil"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/prometheus/client_golang/prometheus"
 
 	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/pkg"
-	"github.com/flanksource/commons/utils"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+)
+
+const (
+	defaultS3PartSize  = s3manager.DefaultUploadPartSize
+	s3ChecksumMetadata = "Sha256"
 )
 
 var (
@@ -44,12 +59,15 @@ type S3Checker struct{}
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *S3Checker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *S3Checker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.S3 {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // Type: returns checker type
@@ -57,10 +75,27 @@ func (c *S3Checker) Type() string {
 	return "s3"
 }
 
-func (c *S3Checker) Check(check v1.S3Check) *pkg.CheckResult {
+func (c *S3Checker) Check(ctx context.Context, check v1.S3Check) *pkg.CheckResult {
 	bucket := check.Bucket
 
-	if _, err := DNSLookup(bucket.Endpoint); err != nil {
+	size, err := parseObjectSize(check.ObjectSize, defaultObjectSize)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid objectSize")
+	}
+	partSize, err := parseObjectSize(check.PartSize, defaultS3PartSize)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid partSize")
+	}
+	if partSize < s3manager.MinUploadPartSize {
+		return invalidErrorf(check, fmt.Errorf("%d < %d", partSize, s3manager.MinUploadPartSize), "partSize is too small")
+	}
+	switch check.Checksum {
+	case "", "md5", "sha256":
+	default:
+		return invalidErrorf(check, fmt.Errorf("unknown checksum %s", check.Checksum), "checksum must be either md5 or sha256")
+	}
+
+	if _, err := DNSLookup(ctx, bucket.Endpoint); err != nil {
 		return Failf(check, "Failed to resolve DNS for %s", bucket.Endpoint)
 	}
 
@@ -84,43 +119,172 @@ func (c *S3Checker) Check(check v1.S3Check) *pkg.CheckResult {
 	yes := true
 	client.Config.S3ForcePathStyle = &yes
 
+	timer := NewTimer()
+	labels := map[string]string{"bucket": bucket.Name}
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+	}
+
 	listTimer := NewTimer()
-	_, err = client.ListObjects(&s3.ListObjectsInput{Bucket: &bucket.Name})
+	_, err = client.ListObjectsWithContext(ctx, &s3.ListObjectsInput{Bucket: &bucket.Name})
 	if err != nil {
 		return Failf(check, "Failed to list objects in bucket %s: %v", bucket.Name, err)
 	}
-	listHistogram.WithLabelValues(bucket.Endpoint, bucket.Name).Observe(listTimer.Elapsed())
+	listTime := listTimer.Elapsed()
+	listHistogram.WithLabelValues(bucket.Endpoint, bucket.Name).Observe(listTime)
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "list_time", Type: metrics.HistogramType, Labels: labels, Value: listTime})
 
-	data := utils.RandomString(16)
-	updateTimer := NewTimer()
-	_, err = client.PutObject(&s3.PutObjectInput{
+	if check.Versioning {
+		versioning, err := client.GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{Bucket: &bucket.Name})
+		if err != nil {
+			return failWithMetrics(result, "Failed to get versioning of bucket %s: %v", bucket.Name, err)
+		}
+		if aws.StringValue(versioning.Status) != s3.BucketVersioningStatusEnabled {
+			return failWithMetrics(result, "Versioning is not enabled on bucket %s", bucket.Name)
+		}
+	}
+	if check.ObjectLock {
+		lock, err := client.GetObjectLockConfigurationWithContext(ctx, &s3.GetObjectLockConfigurationInput{Bucket: &bucket.Name})
+		if err != nil {
+			return failWithMetrics(result, "Failed to get object lock configuration of bucket %s: %v", bucket.Name, err)
+		}
+		if lock.ObjectLockConfiguration == nil || aws.StringValue(lock.ObjectLockConfiguration.ObjectLockEnabled) != s3.ObjectLockEnabledEnabled {
+			return failWithMetrics(result, "Object lock is not enabled on bucket %s", bucket.Name)
+		}
+	}
+
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return unexpectedErrorf(check, err, "failed to generate object")
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	upload := &s3manager.UploadInput{
 		Bucket: &bucket.Name,
 		Key:    &check.ObjectPath,
-		Body:   bytes.NewReader([]byte(data)),
+		Body:   bytes.NewReader(data),
+	}
+	if check.Checksum == "sha256" {
+		upload.Metadata = map[string]*string{s3ChecksumMetadata: aws.String(checksum)}
+	}
+	uploader := s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+		u.PartSize = partSize
 	})
+	uploadTimer := NewTimer()
+	if _, err := uploader.UploadWithContext(ctx, upload); err != nil {
+		return failWithMetrics(result, "Failed to put object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+	}
+	uploadTime := uploadTimer.Elapsed()
+	updateHistogram.WithLabelValues(bucket.Endpoint, bucket.Name).Observe(uploadTime)
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "upload_time", Type: metrics.HistogramType, Labels: labels, Value: uploadTime})
+	if uploadTime > 0 {
+		result.Metrics = append(result.Metrics, pkg.Metric{Name: "upload_throughput", Type: metrics.GaugeType, Labels: labels, Value: megabytes(size) / (uploadTime / 1000)})
+	}
+
+	head, err := client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{Bucket: &bucket.Name, Key: &check.ObjectPath})
 	if err != nil {
-		return Failf(check, "Failed to put object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+		deleteS3Object(client, bucket.Name, check.ObjectPath, nil)
+		return failWithMetrics(result, "Failed to get metadata of object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+	}
+	if err := verifyS3Checksum(check.Checksum, head, data, checksum, partSize); err != nil {
+		deleteS3Object(client, bucket.Name, check.ObjectPath, head.VersionId)
+		return failWithMetrics(result, "Invalid object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
 	}
-	updateHistogram.WithLabelValues(bucket.Endpoint, bucket.Name).Observe(updateTimer.Elapsed())
 
-	timer := NewTimer()
-	obj, err := client.GetObject(&s3.GetObjectInput{
+	downloadTimer := NewTimer()
+	obj, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket: &bucket.Name,
 		Key:    &check.ObjectPath,
 	})
-
 	if err != nil {
-		return Failf(check, "Failed to get object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+		deleteS3Object(client, bucket.Name, check.ObjectPath, head.VersionId)
+		return failWithMetrics(result, "Failed to get object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+	}
+	hash := sha256.New()
+	n, err := io.Copy(hash, obj.Body)
+	obj.Body.Close()
+	if err != nil {
+		deleteS3Object(client, bucket.Name, check.ObjectPath, head.VersionId)
+		return failWithMetrics(result, "Failed to read object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+	}
+	downloadTime := downloadTimer.Elapsed()
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "download_time", Type: metrics.HistogramType, Labels: labels, Value: downloadTime})
+	if downloadTime > 0 {
+		result.Metrics = append(result.Metrics, pkg.Metric{Name: "download_throughput", Type: metrics.GaugeType, Labels: labels, Value: megabytes(n) / (downloadTime / 1000)})
 	}
-	returnedData, _ := ioutil.ReadAll(obj.Body)
-	if string(returnedData) != data {
-		return Failf(check, "Get object doesn't match %s != %s", data, string(returnedData))
+	if actual := hex.EncodeToString(hash.Sum(nil)); n != size || actual != checksum {
+		deleteS3Object(client, bucket.Name, check.ObjectPath, head.VersionId)
+		return failWithMetrics(result, "Get object doesn't match: %d bytes with sha256 %s, expected %d bytes with sha256 %s", n, actual, size, checksum)
 	}
 
-	return &pkg.CheckResult{
-		Check:    check,
-		Pass:     true,
-		Invalid:  false,
-		Duration: int64(timer.Elapsed()),
+	deleteTimer := NewTimer()
+	if _, err := client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{Bucket: &bucket.Name, Key: &check.ObjectPath}); err != nil {
+		return failWithMetrics(result, "Failed to delete object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+	}
+	_, err = client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{Bucket: &bucket.Name, Key: &check.ObjectPath})
+	if err == nil {
+		return failWithMetrics(result, "Object %s in bucket %s still exists after deletion", check.ObjectPath, bucket.Name)
+	} else if reqErr, ok := err.(awserr.RequestFailure); !ok || reqErr.StatusCode() != http.StatusNotFound {
+		return failWithMetrics(result, "Failed to confirm deletion of object %s in bucket %s: %v", check.ObjectPath, bucket.Name, err)
+	}
+	deleteTime := deleteTimer.Elapsed()
+	result.Metrics = append(result.Metrics, pkg.Metric{Name: "delete_time", Type: metrics.HistogramType, Labels: labels, Value: deleteTime})
+
+	// in a versioned bucket the delete only added a delete marker, the version itself is
+	// removed as well unless object lock retains it
+	if head.VersionId != nil && !check.ObjectLock {
+		deleteS3Object(client, bucket.Name, check.ObjectPath, head.VersionId)
+	}
+
+	result.Duration = int64(timer.Elapsed())
+	return result
+}
+
+// verifyS3Checksum compares the ETag or the sha256 metadata of an uploaded object with data. The ETag
+// of objects encrypted with KMS or customer keys is not an md5, so it is not compared
+func verifyS3Checksum(checksum string, head *s3.HeadObjectOutput, data []byte, sha string, partSize int64) error {
+	if checksum == "sha256" {
+		if actual := aws.StringValue(head.Metadata[s3ChecksumMetadata]); actual != sha {
+			return fmt.Errorf("sha256 %s != %s", actual, sha)
+		}
+		return nil
+	}
+	if aws.StringValue(head.ServerSideEncryption) == s3.ServerSideEncryptionAwsKms || head.SSECustomerAlgorithm != nil {
+		return nil
+	}
+	etag := strings.Trim(aws.StringValue(head.ETag), "\"")
+	if expected := s3ETag(data, partSize, etag); etag != expected {
+		return fmt.Errorf("etag %s != %s", etag, expected)
+	}
+	return nil
+}
+
+// s3ETag returns the expected ETag of data, which is the md5 of the object for single part
+// uploads and the md5 of the md5 of every part followed by the number of parts for multipart uploads
+func s3ETag(data []byte, partSize int64, etag string) string {
+	if !strings.Contains(etag, "-") {
+		sum := md5.Sum(data)
+		return hex.EncodeToString(sum[:])
+	}
+	var sums []byte
+	parts := 0
+	for start := int64(0); start < int64(len(data)); start += partSize {
+		end := start + partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		sum := md5.Sum(data[start:end])
+		sums = append(sums, sum[:]...)
+		parts++
+	}
+	sum := md5.Sum(sums)
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), parts)
+}
+
+func deleteS3Object(client *s3.S3, bucket, key string, versionID *string) {
+	if _, err := client.DeleteObject(&s3.DeleteObjectInput{Bucket: &bucket, Key: &key, VersionId: versionID}); err != nil {
+		logger.Errorf("Failed to delete object %s in bucket %s: %v", key, bucket, err)
 	}
 }
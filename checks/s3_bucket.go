@@ -1,10 +1,14 @@
 package checks
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -39,10 +43,24 @@ var (
 		},
 		[]string{"endpoint", "bucket"},
 	)
+	bucketScanGroupLastWrite = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "canary_check_s3_group_last_write",
+			Help: "The last write time of every object group",
+		},
+		[]string{"endpoint", "bucket", "group"},
+	)
+	bucketScanGroupSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "canary_check_s3_group_size",
+			Help: "The size in bytes of the latest object of every object group",
+		},
+		[]string{"endpoint", "bucket", "group"},
+	)
 )
 
 func init() {
-	prometheus.MustRegister(bucketScanObjectCount, bucketScanLastWrite, bucketScanTotalSize)
+	prometheus.MustRegister(bucketScanObjectCount, bucketScanLastWrite, bucketScanTotalSize, bucketScanGroupLastWrite, bucketScanGroupSize)
 }
 
 type S3BucketChecker struct {
@@ -50,12 +68,15 @@ type S3BucketChecker struct {
 
 // Run: Check every entry from config according to Checker interface
 // Returns check result and metrics
-func (c *S3BucketChecker) Run(config v1.CanarySpec) []*pkg.CheckResult {
-	var results []*pkg.CheckResult
+func (c *S3BucketChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
 	for _, conf := range config.S3Bucket {
-		results = append(results, c.Check(conf))
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
 	}
-	return results
+	return group.Wait()
 }
 
 // Type: returns checker type
@@ -63,8 +84,8 @@ func (c *S3BucketChecker) Type() string {
 	return "s3_bucket"
 }
 
-func (c *S3BucketChecker) Check(bucket v1.S3BucketCheck) *pkg.CheckResult {
-	if _, err := DNSLookup(bucket.Endpoint); err != nil {
+func (c *S3BucketChecker) Check(ctx context.Context, bucket v1.S3BucketCheck) *pkg.CheckResult {
+	if _, err := DNSLookup(ctx, bucket.Endpoint); err != nil {
 		return unexpectedErrorf(bucket, err, "failed to resolve DNS")
 	}
 
@@ -89,7 +110,6 @@ func (c *S3BucketChecker) Check(bucket v1.S3BucketCheck) *pkg.CheckResult {
 
 	var marker *string = nil
 
-	var latestObject *s3.Object = nil
 	var objects int
 	var totalSize int64
 	var regex *regexp.Regexp
@@ -100,6 +120,13 @@ func (c *S3BucketChecker) Check(bucket v1.S3BucketCheck) *pkg.CheckResult {
 		}
 		regex = re
 	}
+	groupIndex := 0
+	if bucket.GroupBy != "" {
+		if groupIndex = captureGroupIndex(regex, bucket.GroupBy); groupIndex <= 0 {
+			return invalidErrorf(bucket, fmt.Errorf("%s is not a capture group of %s", bucket.GroupBy, bucket.ObjectPath), "invalid groupBy")
+		}
+	}
+	groups := make(map[string]*objectGroup)
 
 	for {
 		req := &s3.ListObjectsInput{
@@ -107,19 +134,28 @@ func (c *S3BucketChecker) Check(bucket v1.S3BucketCheck) *pkg.CheckResult {
 			Marker:  marker,
 			MaxKeys: aws.Int64(500),
 		}
-		resp, err := client.ListObjects(req)
+		resp, err := client.ListObjectsWithContext(ctx, req)
 		if err != nil {
 			return unexpectedErrorf(bucket, err, "failed to list bucket")
 		}
 
 		for _, obj := range resp.Contents {
-			if regex != nil && !regex.Match([]byte(aws.StringValue(obj.Key))) {
-				continue
+			// without groupBy every matching object is part of the same group
+			var group string
+			if regex != nil {
+				match := regex.FindStringSubmatch(aws.StringValue(obj.Key))
+				if match == nil {
+					continue
+				}
+				if bucket.GroupBy != "" {
+					group = match[groupIndex]
+				}
 			}
 			bucketScanTotalSize.WithLabelValues(bucket.Endpoint, bucket.Bucket).Add(float64(aws.Int64Value(obj.Size)))
-			if latestObject == nil || obj.LastModified.After(aws.TimeValue(latestObject.LastModified)) {
-				latestObject = obj
+			if groups[group] == nil {
+				groups[group] = &objectGroup{}
 			}
+			groups[group].add(obj)
 
 			objects++
 			totalSize += *obj.Size
@@ -136,10 +172,15 @@ func (c *S3BucketChecker) Check(bucket v1.S3BucketCheck) *pkg.CheckResult {
 
 	bucketScanTotalSize.WithLabelValues(bucket.Endpoint, bucket.Bucket).Set(float64(totalSize))
 
-	if latestObject == nil {
+	if len(groups) == 0 {
 		return Failf(bucket, "could not find any matching objects")
 	}
 
+	if bucket.GroupBy != "" {
+		return c.checkGroups(bucket, groups, objects, totalSize)
+	}
+
+	latestObject := groups[""].latest
 	latestObjectAge := time.Now().Sub(aws.TimeValue(latestObject.LastModified))
 	bucketScanLastWrite.WithLabelValues(bucket.Endpoint, bucket.Bucket).Set(float64(latestObject.LastModified.Unix()))
 
@@ -153,9 +194,102 @@ func (c *S3BucketChecker) Check(bucket v1.S3BucketCheck) *pkg.CheckResult {
 		return Failf(bucket, "Latest object is %d bytes required at least %d bytes", latestObjectSize, bucket.MinSize)
 	}
 
+	if drop := groups[""].sizeDrop(); bucket.MaxSizeDrop > 0 && drop > bucket.MaxSizeDrop {
+		return Failf(bucket, "%s", groups[""].sizeDropMessage("Latest object", drop))
+	}
+
 	return Passf(bucket, fmt.Sprintf("maxAge=%s size=%s objects=%d totalSize=%s", age(latestObjectAge), mb(latestObjectSize), objects, mb(totalSize)))
 }
 
+// checkGroups evaluates maxAge, minSize and maxSizeDrop for the latest object of every group,
+// reporting the failing groups by name
+func (c *S3BucketChecker) checkGroups(bucket v1.S3BucketCheck, groups map[string]*objectGroup, objects int, totalSize int64) *pkg.CheckResult {
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	var lastWrite time.Time
+	for _, name := range names {
+		group := groups[name]
+		latestObjectAge := time.Since(aws.TimeValue(group.latest.LastModified))
+		latestObjectSize := aws.Int64Value(group.latest.Size)
+		if group.latest.LastModified.After(lastWrite) {
+			lastWrite = aws.TimeValue(group.latest.LastModified)
+		}
+		bucketScanGroupLastWrite.WithLabelValues(bucket.Endpoint, bucket.Bucket, name).Set(float64(group.latest.LastModified.Unix()))
+		bucketScanGroupSize.WithLabelValues(bucket.Endpoint, bucket.Bucket, name).Set(float64(latestObjectSize))
+
+		if latestObjectAge.Seconds() > float64(bucket.MaxAge) {
+			failures = append(failures, fmt.Sprintf("%s is %s old", name, age(latestObjectAge)))
+		} else if bucket.MinSize > 0 && latestObjectSize < bucket.MinSize {
+			failures = append(failures, fmt.Sprintf("%s is %s required at least %s", name, mb(latestObjectSize), mb(bucket.MinSize)))
+		} else if drop := group.sizeDrop(); bucket.MaxSizeDrop > 0 && drop > bucket.MaxSizeDrop {
+			failures = append(failures, group.sizeDropMessage(name, drop))
+		}
+	}
+	bucketScanLastWrite.WithLabelValues(bucket.Endpoint, bucket.Bucket).Set(float64(lastWrite.Unix()))
+
+	if len(failures) > 0 {
+		return Failf(bucket, "%d of %d groups failed: %s", len(failures), len(groups), strings.Join(failures, ", "))
+	}
+	return Passf(bucket, fmt.Sprintf("groups=%d objects=%d totalSize=%s", len(groups), objects, mb(totalSize)))
+}
+
+// objectGroup tracks the two most recent objects of a group
+type objectGroup struct {
+	latest   *s3.Object
+	previous *s3.Object
+}
+
+func (g *objectGroup) add(obj *s3.Object) {
+	if g.latest == nil || obj.LastModified.After(aws.TimeValue(g.latest.LastModified)) {
+		g.previous = g.latest
+		g.latest = obj
+	} else if g.previous == nil || obj.LastModified.After(aws.TimeValue(g.previous.LastModified)) {
+		g.previous = obj
+	}
+}
+
+// sizeDrop returns the percentage the latest object is smaller than the previous object
+func (g *objectGroup) sizeDrop() int64 {
+	if g.previous == nil || aws.Int64Value(g.previous.Size) == 0 {
+		return 0
+	}
+	previous := aws.Int64Value(g.previous.Size)
+	latest := aws.Int64Value(g.latest.Size)
+	if latest >= previous {
+		return 0
+	}
+	return (previous - latest) * 100 / previous
+}
+
+// sizeDropMessage describes a drop in size from the previous to the latest object of the group
+func (g *objectGroup) sizeDropMessage(name string, drop int64) string {
+	return fmt.Sprintf("%s dropped %d%% from %s to %s", name, drop, mb(aws.Int64Value(g.previous.Size)), mb(aws.Int64Value(g.latest.Size)))
+}
+
+// captureGroupIndex returns the index of the capture group of regex with the given name or number
+func captureGroupIndex(regex *regexp.Regexp, group string) int {
+	if regex == nil {
+		return -1
+	}
+	if i, err := strconv.Atoi(group); err == nil {
+		if i > regex.NumSubexp() {
+			return -1
+		}
+		return i
+	}
+	for i, name := range regex.SubexpNames() {
+		if name == group {
+			return i
+		}
+	}
+	return -1
+}
+
 func age(duration time.Duration) string {
 	if duration.Hours() > 24 {
 		return fmt.Sprintf("%.1fd", duration.Hours()/24)
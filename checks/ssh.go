@@ -0,0 +1,172 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHChecker struct{}
+
+// Type: returns checker type
+func (c *SSHChecker) Type() string {
+	return "ssh"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *SSHChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.SSH {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : Login to the host, verify its host key and optionally run a command
+// Returns check result and metrics
+func (c *SSHChecker) Check(ctx context.Context, check v1.SSHCheck) *pkg.CheckResult {
+	auth, err := sshAuthMethods(check)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid credentials")
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(check)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid host key")
+	}
+	config := &ssh.ClientConfig{
+		User:            check.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	timer := NewTimer()
+	handshakeTimer := NewTimer()
+	// the deadline of the connection also stops the handshake and the command once the check times out
+	conn, err := dialContext(ctx, "tcp", check.GetAddress())
+	if err != nil {
+		return Failf(check, "failed to connect to %s: %v", check.GetAddress(), err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, check.GetAddress(), config)
+	if err != nil {
+		conn.Close()
+		return Failf(check, "failed to connect to %s: %v", check.GetAddress(), err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+	handshakeTime := handshakeTimer.Elapsed()
+
+	result := &pkg.CheckResult{
+		Check: check,
+		Pass:  true,
+		Metrics: []pkg.Metric{
+			{
+				Name:   "handshake_time",
+				Type:   metrics.HistogramType,
+				Labels: map[string]string{"host": check.GetAddress()},
+				Value:  handshakeTime,
+			},
+		},
+	}
+
+	if check.Command == "" {
+		result.Duration = int64(timer.Elapsed())
+		return result
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return Failf(check, "failed to open session: %v", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	commandTimer := NewTimer()
+	exitCode := 0
+	if err := session.Run(check.Command); err != nil {
+		exitErr, ok := err.(*ssh.ExitError)
+		if !ok {
+			return Failf(check, "failed to run %s: %v", check.Command, err)
+		}
+		exitCode = exitErr.ExitStatus()
+	}
+	result.Metrics = append(result.Metrics, pkg.Metric{
+		Name:   "command_time",
+		Type:   metrics.HistogramType,
+		Labels: map[string]string{"host": check.GetAddress()},
+		Value:  commandTimer.Elapsed(),
+	})
+	result.Duration = int64(timer.Elapsed())
+
+	if exitCode != check.ExpectedExitCode {
+		return failWithMetrics(result, "%s exited with %d, expected %d: %s", check.Command, exitCode, check.ExpectedExitCode, strings.TrimSpace(stderr.String()))
+	}
+	if check.ExpectedOutput != "" && !strings.Contains(stdout.String(), check.ExpectedOutput) {
+		return failWithMetrics(result, "output of %s does not contain %s", check.Command, check.ExpectedOutput)
+	}
+	return result
+}
+
+func sshAuthMethods(check v1.SSHCheck) ([]ssh.AuthMethod, error) {
+	if !check.Password.IsResolved() || !check.PrivateKey.IsResolved() {
+		return nil, fmt.Errorf("password and privateKey references are only resolved by the operator")
+	}
+	var methods []ssh.AuthMethod
+	if check.PrivateKey.Value != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(check.PrivateKey.Value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %v", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if check.Password.Value != "" {
+		methods = append(methods, ssh.Password(check.Password.Value))
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("either password or privateKey must be specified")
+	}
+	return methods, nil
+}
+
+// sshHostKeyCallback requires the host key to match either the SHA256 or the legacy MD5 fingerprint of
+// the check, unless verification is explicitly skipped
+func sshHostKeyCallback(check v1.SSHCheck) (ssh.HostKeyCallback, error) {
+	fingerprint := check.HostKeyFingerprint
+	if fingerprint == "" {
+		if !check.InsecureSkipVerify {
+			return nil, fmt.Errorf("hostKeyFingerprint is required unless insecureSkipVerify is set")
+		}
+		logger.Warnf("[ssh] %s accepts any host key as insecureSkipVerify is set", check.GetEndpoint())
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		sha256 := ssh.FingerprintSHA256(key)
+		md5 := ssh.FingerprintLegacyMD5(key)
+		if fingerprint == sha256 || strings.TrimPrefix(fingerprint, "MD5:") == md5 {
+			return nil
+		}
+		return fmt.Errorf("host key fingerprint %s does not match %s", sha256, fingerprint)
+	}, nil
+}
+
+// failWithMetrics marks a result as failed while keeping the metrics collected so far
+func failWithMetrics(result *pkg.CheckResult, msg string, args ...interface{}) *pkg.CheckResult {
+	result.Pass = false
+	result.Message = fmt.Sprintf(msg, args...)
+	return result
+}
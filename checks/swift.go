@@ -0,0 +1,149 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/ncw/swift"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+)
+
+type SwiftChecker struct{}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *SwiftChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.Swift {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Type: returns checker type
+func (c *SwiftChecker) Type() string {
+	return "swift"
+}
+
+func (c *SwiftChecker) Check(ctx context.Context, check v1.SwiftCheck) *pkg.CheckResult {
+	size, err := parseObjectSize(check.ObjectSize, defaultObjectSize)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid objectSize")
+	}
+	store, err := newSwiftStore(ctx, check.SwiftConnection, check.Container)
+	if err != nil {
+		return Failf(check, "Failed to authenticate to %s: %v", check.AuthURL, err)
+	}
+	return checkObjectStore(check, store, check.ObjectPath, size, map[string]string{"container": check.Container})
+}
+
+type SwiftContainerChecker struct{}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *SwiftContainerChecker) Run(ctx context.Context, config v1.CanarySpec) []*pkg.CheckResult {
+	group := newCheckGroup(ctx, config)
+	for _, conf := range config.SwiftContainer {
+		conf := conf
+		group.Run(conf, func(ctx context.Context) *pkg.CheckResult {
+			return c.Check(ctx, conf)
+		})
+	}
+	return group.Wait()
+}
+
+// Type: returns checker type
+func (c *SwiftContainerChecker) Type() string {
+	return "swiftContainer"
+}
+
+func (c *SwiftContainerChecker) Check(ctx context.Context, check v1.SwiftContainerCheck) *pkg.CheckResult {
+	store, err := newSwiftStore(ctx, check.SwiftConnection, check.Container)
+	if err != nil {
+		return Failf(check, "Failed to authenticate to %s: %v", check.AuthURL, err)
+	}
+	return scanObjects(check, store, check.ObjectPath, check.MaxAge, check.MinSize, map[string]string{"container": check.Container})
+}
+
+// swiftStore implements objectStore for a container of an OpenStack Swift object store
+type swiftStore struct {
+	conn      *swift.Connection
+	container string
+}
+
+func newSwiftStore(ctx context.Context, connection v1.SwiftConnection, container string) (*swiftStore, error) {
+	conn := &swift.Connection{
+		AuthUrl:  connection.AuthURL,
+		UserName: connection.Username,
+		ApiKey:   connection.APIKey,
+		Domain:   connection.Domain,
+		Tenant:   connection.Tenant,
+		Region:   connection.Region,
+		Transport: contextTransport{
+			ctx: ctx,
+			next: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: connection.SkipTLSVerify},
+			},
+		},
+	}
+	if err := conn.Authenticate(); err != nil {
+		return nil, err
+	}
+	return &swiftStore{conn: conn, container: container}, nil
+}
+
+// contextTransport sends every request with ctx, as the swift client does not accept a context
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+func (s *swiftStore) List() ([]storedObject, error) {
+	objects, err := s.conn.ObjectsAll(s.container, nil)
+	if err != nil {
+		return nil, err
+	}
+	var stored []storedObject
+	for _, obj := range objects {
+		stored = append(stored, storedObject{Name: obj.Name, Size: obj.Bytes, LastModified: obj.LastModified})
+	}
+	return stored, nil
+}
+
+func (s *swiftStore) Put(name string, data []byte) error {
+	sum := md5.Sum(data)
+	_, err := s.conn.ObjectPut(s.container, name, bytes.NewReader(data), true, hex.EncodeToString(sum[:]), "application/octet-stream", nil)
+	return err
+}
+
+func (s *swiftStore) Get(name string) ([]byte, error) {
+	return s.conn.ObjectGetBytes(s.container, name)
+}
+
+func (s *swiftStore) Delete(name string) error {
+	return s.conn.ObjectDelete(s.container, name)
+}
+
+func (s *swiftStore) Exists(name string) (bool, error) {
+	_, _, err := s.conn.Object(s.container, name)
+	if err == swift.ObjectNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
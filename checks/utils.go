@@ -1,8 +1,14 @@
 package checks
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/flanksource/canary-checker/pkg"
 )
@@ -34,6 +40,28 @@ func Failf(check pkg.GenericCheck, msg string, args ...interface{}) *pkg.CheckRe
 	}
 }
 
+func timedOutf(check pkg.GenericCheck, timeout time.Duration, msg string, args ...interface{}) *pkg.CheckResult {
+	return &pkg.CheckResult{
+		Check:    check,
+		Pass:     false,
+		Invalid:  false,
+		TimedOut: true,
+		Duration: timeout.Milliseconds(),
+		Message:  fmt.Sprintf(msg, args...),
+	}
+}
+
+// blockedf returns the result of a check that was not run as the check identified by rootCause is failing
+func blockedf(check pkg.GenericCheck, rootCause string, msg string, args ...interface{}) *pkg.CheckResult {
+	return &pkg.CheckResult{
+		Check:     check,
+		Pass:      false,
+		Invalid:   false,
+		BlockedBy: rootCause,
+		Message:   fmt.Sprintf(msg, args...),
+	}
+}
+
 func Passf(check pkg.GenericCheck, msg string, args ...interface{}) *pkg.CheckResult {
 	return &pkg.CheckResult{
 		Check:   check,
@@ -43,6 +71,17 @@ func Passf(check pkg.GenericCheck, msg string, args ...interface{}) *pkg.CheckRe
 	}
 }
 
+// maximum number of items listed by summarize
+const maxSummarizedItems = 10
+
+// summarize joins items for use in a check message, truncating long lists
+func summarize(items []string) string {
+	if len(items) > maxSummarizedItems {
+		return fmt.Sprintf("%s and %d more", strings.Join(items[:maxSummarizedItems], ", "), len(items)-maxSummarizedItems)
+	}
+	return strings.Join(items, ", ")
+}
+
 type NameGenerator struct {
 	NamespacesCount int
 	PodsCount       int
@@ -66,3 +105,58 @@ func (n *NameGenerator) PodName(prefix string) string {
 	n.podIndex = (n.PodsCount + 1) % n.PodsCount
 	return name
 }
+
+// dialTimeout limits the time to connect, regardless of the timeout of the check
+const dialTimeout = 30 * time.Second
+
+// dialContext connects to address, setting the deadline of ctx on the connection so that
+// reads and writes fail once the check times out
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// timeoutSeconds returns the number of seconds until the deadline of ctx, for kubernetes API calls
+// that do not accept a context, or nil if ctx has no deadline
+func timeoutSeconds(ctx context.Context) *int64 {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	seconds := int64(math.Ceil(time.Until(deadline).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &seconds
+}
+
+// capTimeout returns timeout, or the time until the deadline of ctx if that is shorter
+func capTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		return time.Until(deadline)
+	}
+	return timeout
+}
+
+// contextReader fails reads once ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
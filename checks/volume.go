@@ -0,0 +1,451 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	canaryv1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/metrics"
+	"github.com/flanksource/commons/logger"
+	perrors "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"golang.org/x/sync/semaphore"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	volumeCheckSelector = "canary-checker.flanksource.com/volumeCheck"
+
+	defaultVolumeImage            = "busybox"
+	defaultVolumeSize             = "1Gi"
+	defaultVolumeWriteSizeMB      = 1
+	defaultVolumeProvisionTimeout = 60 * time.Second
+	defaultVolumeScheduleTimeout  = 60 * time.Second
+	defaultVolumeDeadline         = 5 * time.Minute
+	defaultVolumeDeleteTimeout    = 60 * time.Second
+	volumeMountPath               = "/data"
+)
+
+// matches the duration reported by both busybox and GNU dd, e.g.
+// 1048576 bytes (1.0MB) copied, 0.003662 seconds, 273.1MB/s
+var ddDuration = regexp.MustCompile(`copied, ([0-9.]+) s`)
+
+type VolumeChecker struct {
+	lock *semaphore.Weighted
+	k8s  *kubernetes.Clientset
+	ng   *NameGenerator
+}
+
+func NewVolumeChecker() *VolumeChecker {
+	vc := &VolumeChecker{
+		lock: semaphore.NewWeighted(1),
+		ng:   &NameGenerator{PodsCount: 20},
+	}
+
+	k8sClient, err := pkg.NewK8sClient()
+	if err != nil {
+		logger.Errorf("Failed to create kubernetes config %v", err)
+		return vc
+	}
+
+	vc.k8s = k8sClient
+
+	return vc
+}
+
+// Type: returns checker type
+func (c *VolumeChecker) Type() string {
+	return "volume"
+}
+
+// Run: Check every entry from config according to Checker interface
+// Returns check result and metrics
+func (c *VolumeChecker) Run(ctx context.Context, config canaryv1.CanarySpec) []*pkg.CheckResult {
+	group := newSerialCheckGroup(ctx, config)
+	for _, conf := range config.Volume {
+		conf := conf
+		group.RunWithTimeout(conf, deadlineTimeout(config, conf, conf.Deadline), func(ctx context.Context) *pkg.CheckResult {
+			// the deadline only starts once the check is running
+			deadline, _ := ctx.Deadline()
+			return c.Check(conf, deadline)
+		})
+	}
+	return group.Wait()
+}
+
+// Check : Provision a volume, mount it in a pod and write and read back a file
+// Returns check result and metrics
+func (c *VolumeChecker) Check(check canaryv1.VolumeCheck, checkDeadline time.Time) *pkg.CheckResult {
+	if !c.lock.TryAcquire(1) {
+		logger.Tracef("Check already in progress, skipping")
+		return nil
+	}
+	defer func() { c.lock.Release(1) }()
+
+	// checks without a deadline, timeout or interval are not bounded by their context
+	if checkDeadline.IsZero() {
+		checkDeadline = time.Now().Add(defaultVolumeDeadline)
+	}
+
+	if err := c.Cleanup(check); err != nil {
+		return unexpectedErrorf(check, err, "failed to cleanup old artifacts")
+	}
+
+	pvc, pod, err := c.newVolume(check)
+	if err != nil {
+		return invalidErrorf(check, err, "invalid volume spec")
+	}
+
+	startTimer := NewTimer()
+	logger.Debugf("Running volume check %s", check.Name)
+
+	if _, err := c.k8s.CoreV1().PersistentVolumeClaims(check.Namespace).Create(pvc); err != nil {
+		return unexpectedErrorf(check, err, "unable to create persistent volume claim")
+	}
+	defer func() {
+		c.Cleanup(check)
+	}()
+	// the pod is created straight away, as volumes with a WaitForFirstConsumer
+	// binding mode are only provisioned once a pod uses them
+	if _, err := c.k8s.CoreV1().Pods(check.Namespace).Create(pod); err != nil {
+		return unexpectedErrorf(check, err, "unable to create pod")
+	}
+
+	provisionTimeout := defaultVolumeProvisionTimeout
+	if check.ProvisionTimeout > 0 {
+		provisionTimeout = time.Duration(check.ProvisionTimeout) * time.Millisecond
+	}
+	provisionTime, err := c.waitForBound(pvc, provisionTimeout)
+	if err != nil {
+		return Failf(check, "%v", err)
+	}
+
+	attachTimer := NewTimer()
+	scheduleTimeout := defaultVolumeScheduleTimeout
+	if check.ScheduleTimeout > 0 {
+		scheduleTimeout = time.Duration(check.ScheduleTimeout) * time.Millisecond
+	}
+	pod, err = c.waitForPodStarted(pod, scheduleTimeout)
+	if err != nil {
+		return Failf(check, "%v %s", err, c.podEvents(pod))
+	}
+	attachTime := attachTimer.Elapsed()
+
+	pod, err = c.waitForPodCompleted(pod, checkDeadline)
+	if err != nil {
+		return Failf(check, "%v", err)
+	}
+	logs, err := c.k8s.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{}).Do().Raw()
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to get logs of pod %s", pod.Name)
+	}
+	output := strings.TrimSpace(string(logs))
+	logger.Debugf("%s provision=%0.0f attach=%0.0f wall=%s output=%s", pod.Name, provisionTime, attachTime, startTimer, output)
+
+	pass := pod.Status.Phase == v1.PodSucceeded
+	message := ""
+	if !pass {
+		message = fmt.Sprintf("write/read of %s failed: %s", volumeMountPath, output)
+	}
+
+	writeTime, writeErr := ddTime(output, "write")
+	readTime, readErr := ddTime(output, "read")
+	if pass && (writeErr != nil || readErr != nil) {
+		pass = false
+		message = fmt.Sprintf("unexpected output: %s", output)
+	}
+
+	deleteTime, err := c.deleteVolume(check, pod, pvc)
+	if err != nil {
+		return unexpectedErrorf(check, err, "failed to delete volume")
+	}
+
+	sizeMB := float64(check.WriteSizeMB)
+	if sizeMB == 0 {
+		sizeMB = defaultVolumeWriteSizeMB
+	}
+	labels := map[string]string{"volumeCheck": check.Name, "storageClass": check.StorageClass}
+	result := &pkg.CheckResult{
+		Check:    check,
+		Pass:     pass,
+		Duration: int64(startTimer.Elapsed()),
+		Message:  message,
+		Metrics: []pkg.Metric{
+			{
+				Name:   "provision_time",
+				Type:   metrics.HistogramType,
+				Labels: labels,
+				Value:  provisionTime,
+			},
+			{
+				Name:   "attach_time",
+				Type:   metrics.HistogramType,
+				Labels: labels,
+				Value:  attachTime,
+			},
+			{
+				Name:   "delete_time",
+				Type:   metrics.HistogramType,
+				Labels: labels,
+				Value:  deleteTime,
+			},
+		},
+	}
+	if writeErr == nil && readErr == nil {
+		result.Metrics = append(result.Metrics,
+			pkg.Metric{Name: "write_time", Type: metrics.HistogramType, Labels: labels, Value: writeTime},
+			pkg.Metric{Name: "read_time", Type: metrics.HistogramType, Labels: labels, Value: readTime},
+		)
+		if writeTime > 0 && readTime > 0 {
+			result.Metrics = append(result.Metrics,
+				pkg.Metric{Name: "write_throughput", Type: metrics.GaugeType, Labels: labels, Value: sizeMB / (writeTime / 1000)},
+				pkg.Metric{Name: "read_throughput", Type: metrics.GaugeType, Labels: labels, Value: sizeMB / (readTime / 1000)},
+			)
+		}
+	}
+	return result
+}
+
+func (c *VolumeChecker) newVolume(check canaryv1.VolumeCheck) (*v1.PersistentVolumeClaim, *v1.Pod, error) {
+	size := check.Size
+	if size == "" {
+		size = defaultVolumeSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid size %s: %v", size, err)
+	}
+	accessMode := v1.ReadWriteOnce
+	if check.AccessMode != "" {
+		accessMode = v1.PersistentVolumeAccessMode(check.AccessMode)
+	}
+	image := check.Image
+	if image == "" {
+		image = defaultVolumeImage
+	}
+	sizeMB := check.WriteSizeMB
+	if sizeMB == 0 {
+		sizeMB = defaultVolumeWriteSizeMB
+	}
+
+	name := c.ng.PodName(check.Name + "-")
+	labels := map[string]string{
+		volumeCheckSelector: c.volumeCheckSelectorValue(check),
+		podGeneralSelector:  "true",
+	}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: check.Namespace,
+			Labels:    labels,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{accessMode},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: quantity},
+			},
+		},
+	}
+	if check.StorageClass != "" {
+		pvc.Spec.StorageClassName = &check.StorageClass
+	}
+
+	conv := ""
+	if check.Fsync {
+		conv = "conv=fsync"
+	}
+	script := strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("dd if=/dev/urandom of=/tmp/canary bs=1048576 count=%d 2>/dev/null", sizeMB),
+		fmt.Sprintf("echo write $(dd if=/tmp/canary of=%s/canary bs=1048576 %s 2>&1 | grep copied)", volumeMountPath, conv),
+		fmt.Sprintf("echo read $(dd if=%s/canary of=/dev/null bs=1048576 2>&1 | grep copied)", volumeMountPath),
+		fmt.Sprintf(`[ "$(md5sum < /tmp/canary)" = "$(md5sum < %s/canary)" ] || { echo checksum mismatch; exit 1; }`, volumeMountPath),
+	}, "\n")
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: check.Namespace,
+			Labels:    labels,
+		},
+		Spec: v1.PodSpec{
+			RestartPolicy:     v1.RestartPolicyNever,
+			PriorityClassName: check.PriorityClass,
+			Containers: []v1.Container{
+				{
+					Name:    "volume",
+					Image:   image,
+					Command: []string{"sh", "-c", script},
+					VolumeMounts: []v1.VolumeMount{
+						{Name: "volume", MountPath: volumeMountPath},
+					},
+				},
+			},
+			Volumes: []v1.Volume{
+				{
+					Name: "volume",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+					},
+				},
+			},
+		},
+	}
+	return pvc, pod, nil
+}
+
+// waitForBound waits for the claim to be bound and returns the time it took in milliseconds
+func (c *VolumeChecker) waitForBound(pvc *v1.PersistentVolumeClaim, timeout time.Duration) (float64, error) {
+	claims := c.k8s.CoreV1().PersistentVolumeClaims(pvc.Namespace)
+	timer := NewTimer()
+	start := time.Now()
+	for {
+		claim, err := claims.Get(pvc.Name, metav1.GetOptions{})
+		if err == nil && claim.Status.Phase == v1.ClaimBound {
+			return timer.Elapsed(), nil
+		}
+		if start.Add(timeout).Before(time.Now()) {
+			if err != nil {
+				return 0, perrors.Wrapf(err, "Timeout exceeded waiting for %s to be bound", pvc.Name)
+			}
+			return 0, fmt.Errorf("Timeout exceeded waiting for %s to be bound, phase is %s %s", pvc.Name, claim.Status.Phase, c.events(claim.Namespace, "PersistentVolumeClaim", claim.Name))
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// waitForPodStarted waits for the pod to be past the Pending phase, i.e. the volume is
+// attached and mounted and the container has been started
+func (c *VolumeChecker) waitForPodStarted(pod *v1.Pod, timeout time.Duration) (*v1.Pod, error) {
+	pods := c.k8s.CoreV1().Pods(pod.Namespace)
+	start := time.Now()
+	for {
+		p, err := pods.Get(pod.Name, metav1.GetOptions{})
+		if err == nil && p.Status.Phase != v1.PodPending {
+			return p, nil
+		}
+		if start.Add(timeout).Before(time.Now()) {
+			return pod, fmt.Errorf("Timeout exceeded waiting for %s to start, error: %v", pod.Name, err)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// waitForPodCompleted waits for the pod to succeed or fail, or returns an
+// error if the deadline is exceeded
+func (c *VolumeChecker) waitForPodCompleted(pod *v1.Pod, deadline time.Time) (*v1.Pod, error) {
+	pods := c.k8s.CoreV1().Pods(pod.Namespace)
+	for {
+		p, err := pods.Get(pod.Name, metav1.GetOptions{})
+		if err == nil && (p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed) {
+			return p, nil
+		}
+		if deadline.Before(time.Now()) {
+			return pod, fmt.Errorf("Deadline exceeded waiting for %s to complete, error: %v", pod.Name, err)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// deleteVolume deletes the pod and the claim, waiting for the claim to be
+// deleted and returns the time it took in milliseconds
+func (c *VolumeChecker) deleteVolume(check canaryv1.VolumeCheck, pod *v1.Pod, pvc *v1.PersistentVolumeClaim) (float64, error) {
+	timer := NewTimer()
+	if err := c.k8s.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return 0, perrors.Wrapf(err, "Failed to delete pod %s in namespace %s", pod.Name, pod.Namespace)
+	}
+	claims := c.k8s.CoreV1().PersistentVolumeClaims(pvc.Namespace)
+	if err := claims.Delete(pvc.Name, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return 0, perrors.Wrapf(err, "Failed to delete persistent volume claim %s in namespace %s", pvc.Name, pvc.Namespace)
+	}
+
+	timeout := defaultVolumeDeleteTimeout
+	if check.DeleteTimeout > 0 {
+		timeout = time.Duration(check.DeleteTimeout) * time.Millisecond
+	}
+	start := time.Now()
+	for {
+		_, err := claims.Get(pvc.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return timer.Elapsed(), nil
+		}
+		if start.Add(timeout).Before(time.Now()) {
+			return timer.Elapsed(), fmt.Errorf("Timeout exceeded waiting for %s to be deleted", pvc.Name)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+func (c *VolumeChecker) Cleanup(check canaryv1.VolumeCheck) error {
+	if c.k8s == nil {
+		return fmt.Errorf("Connection to k8s not established")
+	}
+	listOptions := metav1.ListOptions{LabelSelector: c.volumeCheckSelector(check)}
+
+	err := c.k8s.CoreV1().Pods(check.Namespace).DeleteCollection(nil, listOptions)
+	if err != nil && !errors.IsNotFound(err) {
+		return perrors.Wrapf(err, "Failed to delete pods for check %s in namespace %s : %v", check.Name, check.Namespace, err)
+	}
+	err = c.k8s.CoreV1().PersistentVolumeClaims(check.Namespace).DeleteCollection(nil, listOptions)
+	if err != nil && !errors.IsNotFound(err) {
+		return perrors.Wrapf(err, "Failed to delete persistent volume claims for check %s in namespace %s : %v", check.Name, check.Namespace, err)
+	}
+	return nil
+}
+
+// podEvents returns the warnings of the pod, e.g. FailedAttachVolume or FailedMount
+func (c *VolumeChecker) podEvents(pod *v1.Pod) string {
+	return c.events(pod.Namespace, "Pod", pod.Name)
+}
+
+func (c *VolumeChecker) events(namespace, kind, name string) string {
+	events, err := c.k8s.CoreV1().Events(namespace).List(metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s,type=Warning", kind, name),
+	})
+	if err != nil {
+		logger.Debugf("failed to list events of %s %s: %v", kind, name, err)
+		return ""
+	}
+	var msg []string
+	for _, event := range events.Items {
+		msg = append(msg, fmt.Sprintf("[reason=%s message=%s]", event.Reason, event.Message))
+	}
+	return strings.Join(msg, " ")
+}
+
+func (c *VolumeChecker) volumeCheckSelectorValue(check canaryv1.VolumeCheck) string {
+	return fmt.Sprintf("%s.%s", check.Name, check.Namespace)
+}
+
+func (c *VolumeChecker) volumeCheckSelector(check canaryv1.VolumeCheck) string {
+	return fmt.Sprintf("%s=%s", volumeCheckSelector, c.volumeCheckSelectorValue(check))
+}
+
+// ddTime returns the duration in milliseconds reported by dd on the output line starting with prefix
+func ddTime(output, prefix string) (float64, error) {
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, prefix+" ") {
+			continue
+		}
+		match := ddDuration.FindStringSubmatch(line)
+		if match == nil {
+			return 0, fmt.Errorf("no duration in %s", line)
+		}
+		seconds, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, err
+		}
+		return seconds * 1000, nil
+	}
+	return 0, fmt.Errorf("no %s output", prefix)
+}
@@ -5,11 +5,13 @@ import (
 	"os"
 
 	canaryv1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/checks"
 	"github.com/flanksource/canary-checker/pkg"
 	"github.com/flanksource/canary-checker/pkg/aggregate"
 	"github.com/flanksource/canary-checker/pkg/api"
 	"github.com/flanksource/canary-checker/pkg/cache"
 	"github.com/flanksource/canary-checker/pkg/controllers"
+	"github.com/flanksource/canary-checker/pkg/health"
 	"github.com/flanksource/commons/logger"
 	"github.com/go-logr/zapr"
 	"github.com/spf13/cobra"
@@ -33,6 +35,11 @@ func init() {
 	Operator.Flags().IntVar(&metricsPort, "metricsPort", 8081, "Port to expose a health dashboard ")
 	Operator.Flags().IntVar(&webhookPort, "webhookPort", 8082, "Port for webhooks ")
 	Operator.Flags().BoolVar(&dev, "dev", false, "Run in development mode")
+	Operator.Flags().IntVar(&checks.MaxConcurrency, "maxConcurrency", 20, "Maximum number of checks to run at the same time, 0 for no limit")
+	Operator.Flags().IntVar(&checks.MaxConcurrencyPerType, "maxConcurrencyPerType", 10, "Maximum number of checks of the same type to run at the same time, 0 for no limit")
+	Operator.Flags().IntVar(&health.FailureThreshold, "failureThreshold", 2, "Default number of consecutive failures required to fail a check")
+	Operator.Flags().IntVar(&health.SuccessThreshold, "successThreshold", 1, "Default number of consecutive successes required for a failed check to pass")
+	Operator.Flags().IntVar(&checks.Retries, "retries", 0, "Default number of times a failed check is retried")
 	Operator.Flags().StringVar(&includeNamespace, "include-namespace", "", "Watch only specified namespaces, otherwise watch all")
 	Operator.Flags().StringVar(&includeCheck, "include-check", "", "Run matching canaries - useful for debugging")
 
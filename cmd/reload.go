@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/checks"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/canary-checker/pkg/cache"
+	"github.com/flanksource/canary-checker/pkg/health"
+	"github.com/flanksource/commons/logger"
+	"github.com/fsnotify/fsnotify"
+	"github.com/robfig/cron/v3"
+)
+
+// ReloadDelay is the time to wait for more changes to the config files before reloading them
+var ReloadDelay = time.Second
+
+// configScheduler schedules the checks of the config files of serve. Checks that depend on each other
+// share a job so that they wait for each other, every other check has its own job. Jobs are only
+// rescheduled when their checks or the settings of their config file change
+type configScheduler struct {
+	paths    []string
+	interval uint64
+	cron     *cron.Cron
+	mtx      sync.Mutex
+	// chain wraps the jobs, so that the first run of a job and its scheduled runs skip each other
+	// while one of them is in progress
+	chain  cron.Chain
+	jobs   map[string]configJob
+	checks map[string]bool
+}
+
+type configJob struct {
+	id          cron.EntryID
+	fingerprint string
+}
+
+func newConfigScheduler(paths []string, interval uint64, scheduler *cron.Cron) *configScheduler {
+	return &configScheduler{
+		paths:    paths,
+		interval: interval,
+		cron:     scheduler,
+		chain:    cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+		jobs:     make(map[string]configJob),
+		checks:   make(map[string]bool),
+	}
+}
+
+// load reads and validates every config file, failing if any of them is invalid
+func (s *configScheduler) load() (map[string]v1.CanarySpec, error) {
+	files, err := pkg.ConfigFiles(s.paths)
+	if err != nil {
+		return nil, err
+	}
+	configs := make(map[string]v1.CanarySpec)
+	keys := make(map[string]string)
+	for _, file := range files {
+		config, err := pkg.LoadConfig(file)
+		if err != nil {
+			return nil, err
+		}
+		// the interval of the config file takes precedence over the default interval
+		if config.Interval == 0 {
+			config.Interval = int64(s.interval)
+		}
+		if err := checks.Validate("", config); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		// the checks of all files share the status page and metrics
+		for _, check := range pkg.AllChecks(config) {
+			key := pkg.CheckKey("", check)
+			if other, found := keys[key]; found && other != file {
+				logger.Warnf("%s: check %s is also defined in %s and shares its history", file, key, other)
+			}
+			keys[key] = file
+		}
+		configs[file] = config
+	}
+	return configs, nil
+}
+
+// Reload schedules the checks of the config files, keeping the jobs whose config has not changed. The
+// running jobs are left untouched if any config file is invalid
+func (s *configScheduler) Reload() error {
+	configs, err := s.load()
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	jobs := make(map[string]configJob)
+	checkKeys := make(map[string]bool)
+	var current []pkg.GenericCheck
+	scheduled, unchanged := 0, 0
+	for file, config := range configs {
+		for _, check := range pkg.AllChecks(config) {
+			checkKeys[pkg.CheckKey("", check)] = true
+			current = append(current, check)
+		}
+		for _, group := range dependencyGroups(config) {
+			groupKey := strings.Join(group, ",")
+			spec := pkg.WithChecks(config, group)
+			fingerprint, err := json.Marshal(spec)
+			if err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			// the checkers are grouped by schedule, only the groups with the checkers of the checks have
+			// anything to run
+			for schedule, checkers := range checks.Scheduled(spec) {
+				key := file + " " + groupKey + " " + schedule
+				if job, found := s.jobs[key]; found && job.fingerprint == string(fingerprint) {
+					jobs[key] = job
+					delete(s.jobs, key)
+					unchanged++
+					continue
+				}
+				job := s.chain.Then(cron.FuncJob(serveJob(spec, schedule, checkers)))
+				id, err := s.cron.AddJob(schedule, job)
+				if err != nil {
+					logger.Errorf("%s: invalid schedule %s of %s: %v", file, schedule, groupKey, err)
+					continue
+				}
+				logger.Debugf("Running %s of %s on schedule %s", groupKey, file, schedule)
+				jobs[key] = configJob{id: id, fingerprint: string(fingerprint)}
+				scheduled++
+				// run the checks as soon as they are scheduled
+				go job.Run()
+			}
+		}
+	}
+	// the remaining jobs are for checks that changed or were removed
+	for _, job := range s.jobs {
+		s.cron.Remove(job.id)
+	}
+	removed := len(s.jobs)
+	s.jobs = jobs
+
+	// checks that were renamed or removed no longer show up, the history of the others is kept
+	for key := range s.checks {
+		if !checkKeys[key] {
+			health.Remove(key)
+		}
+	}
+	s.checks = checkKeys
+	cache.Prune("", current)
+	logger.Infof("Loaded %d config files: %d jobs scheduled, %d unchanged and %d removed", len(configs), scheduled, unchanged, removed)
+	return nil
+}
+
+// dependencyGroups returns the keys of the checks of config grouped by the checks they depend on or that
+// depend on them, directly or through other checks of config
+func dependencyGroups(config v1.CanarySpec) [][]string {
+	parent := make(map[string]string)
+	find := func(key string) string {
+		for parent[key] != key {
+			key = parent[key]
+		}
+		return key
+	}
+	var keys []string
+	for _, check := range pkg.AllChecks(config) {
+		key := pkg.CheckKey("", check)
+		if _, found := parent[key]; !found {
+			parent[key] = key
+			keys = append(keys, key)
+		}
+	}
+	for _, check := range pkg.AllChecks(config) {
+		for _, ref := range check.GetDependsOn() {
+			if _, found := parent[ref]; found {
+				parent[find(ref)] = find(pkg.CheckKey("", check))
+			}
+		}
+	}
+
+	members := make(map[string][]string)
+	var roots []string
+	for _, key := range keys {
+		root := find(key)
+		if _, found := members[root]; !found {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], key)
+	}
+	var groups [][]string
+	for _, root := range roots {
+		sort.Strings(members[root])
+		groups = append(groups, members[root])
+	}
+	return groups
+}
+
+// Watch reloads the config files whenever the files or directories in paths change, waiting for
+// ReloadDelay after the last change
+func (s *configScheduler) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	watched := make(map[string]bool)
+	for _, path := range s.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		// files are watched through their directory, as editors and config map mounts replace them
+		dir := path
+		if !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+		watched[dir] = true
+	}
+
+	go func() {
+		var timer *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				logger.Debugf("Config changed: %s", event)
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(ReloadDelay, func() {
+					if err := s.Reload(); err != nil {
+						logger.Errorf("Failed to reload config, keeping the current config: %v", err)
+					}
+				})
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Errorf("Failed to watch config: %v", err)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flanksource/canary-checker/pkg/health"
+	"github.com/robfig/cron/v3"
+)
+
+// writeConfig writes a config file with an http check for every path, named after the path without
+// its query, or removes the file if there are no paths. Paths are followed by the checks they depend
+// on, separated by spaces
+func writeConfig(dir, file, server string, paths []string) error {
+	if len(paths) == 0 {
+		return os.Remove(filepath.Join(dir, file))
+	}
+	config := "interval: 3600\nhttp:\n"
+	for _, path := range paths {
+		fields := strings.Fields(path)
+		name := strings.Split(fields[0], "?")[0]
+		config += fmt.Sprintf("  - name: %s\n    endpoint: %s/%s\n    responseCodes: [200]\n", name, server, fields[0])
+		if len(fields) > 1 {
+			config += fmt.Sprintf("    dependsOn: [%s]\n", strings.Join(fields[1:], ", "))
+		}
+	}
+	return ioutil.WriteFile(filepath.Join(dir, file), []byte(config), 0644)
+}
+
+// newServer returns a server that fails the requests with fail in their query
+func newServer() *httptest.Server {
+	return httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if strings.Contains(r.URL.RawQuery, "fail") {
+			w.WriteHeader(nethttp.StatusInternalServerError)
+		}
+	}))
+}
+
+// jobIDs returns the ids of the jobs of s by the keys of their checks
+func jobIDs(s *configScheduler) map[string]cron.EntryID {
+	ids := make(map[string]cron.EntryID)
+	for key, job := range s.jobs {
+		ids[strings.SplitN(key, " ", 3)[1]] = job.id
+	}
+	return ids
+}
+
+func TestReload(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	scheduler := cron.New()
+	s := newConfigScheduler([]string{dir}, 30, scheduler)
+	tests := []struct {
+		name string
+		// files are the paths of the checks of the files to write, or nil to remove a file
+		files map[string][]string
+		// rescheduled are the checks that get a new job, the jobs of the other checks are kept
+		rescheduled []string
+		want        []string
+		invalid     bool
+	}{
+		{
+			name:        "initial files",
+			files:       map[string][]string{"a.yaml": {"a1", "a2"}, "b.yaml": {"b1"}},
+			rescheduled: []string{"http/a1", "http/a2", "http/b1"},
+			want:        []string{"http/a1", "http/a2", "http/b1"},
+		},
+		{
+			name:  "unchanged files",
+			files: map[string][]string{"a.yaml": {"a1", "a2"}},
+			want:  []string{"http/a1", "http/a2", "http/b1"},
+		},
+		{
+			name:        "changed check",
+			files:       map[string][]string{"a.yaml": {"a1", "a2?changed"}},
+			rescheduled: []string{"http/a2"},
+			want:        []string{"http/a1", "http/a2", "http/b1"},
+		},
+		{
+			name:        "added file",
+			files:       map[string][]string{"c.yaml": {"c1"}},
+			rescheduled: []string{"http/c1"},
+			want:        []string{"http/a1", "http/a2", "http/b1", "http/c1"},
+		},
+		{
+			name:  "removed file",
+			files: map[string][]string{"b.yaml": nil},
+			want:  []string{"http/a1", "http/a2", "http/c1"},
+		},
+		{
+			name:  "removed check",
+			files: map[string][]string{"a.yaml": {"a2?changed"}},
+			want:  []string{"http/a2", "http/c1"},
+		},
+		{
+			name:        "dependent checks",
+			files:       map[string][]string{"d.yaml": {"d1", "d2 http/d1", "d3"}},
+			rescheduled: []string{"http/d1,http/d2", "http/d3"},
+			want:        []string{"http/a2", "http/c1", "http/d1,http/d2", "http/d3"},
+		},
+		{
+			name:        "changed dependency",
+			files:       map[string][]string{"d.yaml": {"d1?changed", "d2 http/d1", "d3"}},
+			rescheduled: []string{"http/d1,http/d2"},
+			want:        []string{"http/a2", "http/c1", "http/d1,http/d2", "http/d3"},
+		},
+		{
+			name:        "new dependency",
+			files:       map[string][]string{"d.yaml": {"d1?changed", "d2 http/d1", "d3 http/d2"}},
+			rescheduled: []string{"http/d1,http/d2,http/d3"},
+			want:        []string{"http/a2", "http/c1", "http/d1,http/d2,http/d3"},
+		},
+		{
+			name:    "dependency cycle",
+			files:   map[string][]string{"d.yaml": {"d1?changed http/d3", "d2 http/d1", "d3 http/d2"}},
+			want:    []string{"http/a2", "http/c1", "http/d1,http/d2,http/d3"},
+			invalid: true,
+		},
+	}
+	for _, tc := range tests {
+		for file, paths := range tc.files {
+			if err := writeConfig(dir, file, server.URL, paths); err != nil {
+				t.Fatal(err)
+			}
+		}
+		previous := jobIDs(s)
+		if err := s.Reload(); tc.invalid && err == nil {
+			t.Errorf("Test %s failed. Expected an error", tc.name)
+		} else if !tc.invalid && err != nil {
+			t.Fatalf("Test %s failed. Unexpected error: %v", tc.name, err)
+		}
+		ids := jobIDs(s)
+
+		var found []string
+		for key := range ids {
+			found = append(found, key)
+		}
+		sort.Strings(found)
+		if strings.Join(found, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("Test %s failed. Expected jobs for %v, but found %v", tc.name, tc.want, found)
+		}
+		if len(scheduler.Entries()) != len(tc.want) {
+			t.Errorf("Test %s failed. Expected %d scheduled jobs, but found %d", tc.name, len(tc.want), len(scheduler.Entries()))
+		}
+		rescheduled := make(map[string]bool)
+		for _, key := range tc.rescheduled {
+			rescheduled[key] = true
+		}
+		for key, id := range ids {
+			if changed := previous[key] != id; changed != rescheduled[key] {
+				t.Errorf("Test %s failed. Expected %s to be rescheduled %v, but found %v", tc.name, key, rescheduled[key], changed)
+			}
+		}
+	}
+}
+
+func TestReloadDependencies(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := writeConfig(dir, "e.yaml", server.URL, []string{"e2 http/e1", "e3 http/e2", "e1?fail"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, key := range []string{"http/e1", "http/e2", "http/e3"} {
+			health.Remove(key)
+		}
+	}()
+
+	// the dependent checks run once the failing check they depend on has run, even on the first run
+	s := newConfigScheduler([]string{dir}, 30, cron.New())
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, key := range []string{"http/e2", "http/e3"} {
+		var rootCause string
+		found := false
+		for start := time.Now(); !found && time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+			_, rootCause, _, found = health.Status(key)
+		}
+		if rootCause != "http/e1" {
+			t.Errorf("Expected %s to be blocked by http/e1, but found %q (reported %v)", key, rootCause, found)
+		}
+	}
+}
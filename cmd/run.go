@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -20,7 +21,8 @@ var Run = &cobra.Command{
 		failed := 0
 		for _, result := range RunChecks(config) {
 			fmt.Println(result)
-			if !result.Pass {
+			// failures during maintenance are expected
+			if !result.Pass && !result.Maintenance {
 				failed++
 			}
 		}
@@ -32,14 +34,10 @@ var Run = &cobra.Command{
 
 func init() {
 	Run.Flags().StringP("configfile", "c", "", "Specify configfile")
+	Run.Flags().IntVar(&checks.Retries, "retries", 0, "Default number of times a failed check is retried")
+	Run.Flags().IntVar(&checks.MaxConcurrency, "maxConcurrency", 20, "Maximum number of checks to run at the same time, 0 for no limit")
+	Run.Flags().IntVar(&checks.MaxConcurrencyPerType, "maxConcurrencyPerType", 10, "Maximum number of checks of the same type to run at the same time, 0 for no limit")
 }
 func RunChecks(config v1.CanarySpec) []*pkg.CheckResult {
-
-	var results []*pkg.CheckResult
-
-	for _, c := range checks.All {
-		results = append(results, c.Run(config)...)
-	}
-
-	return results
+	return checks.RunChecks(context.Background(), "", checks.All, config)
 }
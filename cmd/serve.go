@@ -1,24 +1,27 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	nethttp "net/http"
-	"time"
 
 	_ "net/http/pprof"
 
+	v1 "github.com/flanksource/canary-checker/api/v1"
 	"github.com/flanksource/canary-checker/checks"
 	"github.com/flanksource/canary-checker/pkg"
 	"github.com/flanksource/canary-checker/pkg/aggregate"
 	"github.com/flanksource/canary-checker/pkg/api"
 	"github.com/flanksource/canary-checker/pkg/cache"
+	"github.com/flanksource/canary-checker/pkg/health"
+	"github.com/flanksource/canary-checker/pkg/maintenance"
 	"github.com/flanksource/canary-checker/pkg/metrics"
 	"github.com/flanksource/canary-checker/statuspage"
 	"github.com/flanksource/commons/logger"
-	"github.com/go-co-op/gocron"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
 )
 
@@ -26,33 +29,42 @@ var Serve = &cobra.Command{
 	Use:   "serve",
 	Short: "Start a server to execute checks ",
 	Run: func(cmd *cobra.Command, args []string) {
-		configfile, _ := cmd.Flags().GetString("configfile")
-		config := pkg.ParseConfig(configfile)
+		configfiles, _ := cmd.Flags().GetStringSlice("configfile")
+		interval, _ := cmd.Flags().GetUint64("interval")
+		watch, _ := cmd.Flags().GetBool("watch")
 
-		interval, _ := cmd.Flags().GetInt64("interval")
-
-		config.Interval = interval
-		logger.Infof("Running checks every %d seconds", config.Interval)
-
-		scheduler := gocron.NewScheduler(time.UTC)
-
-		for _, _c := range checks.All {
-			c := _c
-			scheduler.Every(uint64(interval)).Seconds().StartImmediately().Do(func() {
-				go func() {
-					for _, result := range c.Run(config) {
-						cache.AddCheck("", result)
-						metrics.Record("", "", result)
-					}
-				}()
-			})
+		scheduler := cron.New()
+		configs := newConfigScheduler(configfiles, interval, scheduler)
+		if err := configs.Reload(); err != nil {
+			logger.Fatalf("Invalid config: %v", err)
+		}
+		if watch {
+			if err := configs.Watch(); err != nil {
+				logger.Fatalf("Failed to watch config: %v", err)
+			}
 		}
 
-		scheduler.StartAsync()
+		scheduler.Start()
 		serve(cmd)
 	},
 }
 
+// serveJob returns a job running the checkers on schedule and recording their results
+func serveJob(config v1.CanarySpec, schedule string, checkers []checks.Checker) func() {
+	// checks waiting for pods or jobs use the interval as their deadline
+	if interval, err := checks.ScheduleInterval(schedule); err == nil {
+		config.Interval = int64(interval.Seconds())
+	}
+	return func() {
+		for _, result := range checks.RunChecks(context.Background(), "", checkers, config) {
+			health.Update(pkg.CheckKey("", result.Check), config, result)
+			cache.AddCheck("", result)
+			// checks of serve do not belong to a canary, the check label identifies them
+			metrics.Record("", "", result)
+		}
+	}
+}
+
 func serve(cmd *cobra.Command) {
 	httpPort, _ := cmd.Flags().GetInt("httpPort")
 	dev, _ := cmd.Flags().GetBool("dev")
@@ -64,6 +76,8 @@ func serve(cmd *cobra.Command) {
 	}
 	nethttp.HandleFunc("/api", api.Handler)
 	nethttp.HandleFunc("/api/aggregate", aggregate.Handler)
+	nethttp.HandleFunc("/api/silences", maintenance.Handler)
+	nethttp.HandleFunc("/api/silences/", maintenance.Handler)
 
 	addr := fmt.Sprintf("0.0.0.0:%d", httpPort)
 	logger.Infof("Starting health dashboard at http://%s", addr)
@@ -89,11 +103,16 @@ func devRootPageHandler(w nethttp.ResponseWriter, req *nethttp.Request) {
 }
 
 func init() {
-	Serve.Flags().StringP("configfile", "c", "", "Specify configfile")
+	Serve.Flags().StringSliceP("configfile", "c", []string{}, "Config files or directories of YAML config files, which can be repeated")
+	Serve.Flags().Bool("watch", true, "Reload the config files when they change")
 	Serve.Flags().Int("httpPort", 8080, "Port to expose a health dashboard ")
 	Serve.Flags().Uint64("interval", 30, "Default interval (in seconds) to run checks on")
-	Serve.Flags().Int("failureThreshold", 2, "Default Number of consecutive failures required to fail a check")
+	Serve.Flags().IntVar(&health.FailureThreshold, "failureThreshold", 2, "Default Number of consecutive failures required to fail a check")
+	Serve.Flags().IntVar(&health.SuccessThreshold, "successThreshold", 1, "Default number of consecutive successes required for a failed check to pass")
+	Serve.Flags().IntVar(&checks.Retries, "retries", 0, "Default number of times a failed check is retried")
 	Serve.Flags().Bool("dev", false, "Run in development mode")
+	Serve.Flags().IntVar(&checks.MaxConcurrency, "maxConcurrency", 20, "Maximum number of checks to run at the same time, 0 for no limit")
+	Serve.Flags().IntVar(&checks.MaxConcurrencyPerType, "maxConcurrencyPerType", 10, "Maximum number of checks of the same type to run at the same time, 0 for no limit")
 	Serve.Flags().IntVar(&cache.Size, "maxStatusCheckCount", 5, "Maximum number of past checks in the status page")
 	Serve.Flags().StringSliceVar(&aggregate.Servers, "aggregateServers", []string{}, "Aggregate check results from multiple servers in the status page")
 	Serve.Flags().StringVar(&api.ServerName, "name", "local", "Server name shown in aggregate dashboard")
@@ -8,7 +8,7 @@ require (
 	github.com/chartmuseum/helm-push v0.8.1
 	github.com/docker/docker v1.13.1
 	github.com/flanksource/commons v1.4.0
-	github.com/go-co-op/gocron v0.2.0
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/go-ldap/ldap/v3 v3.1.7
 	github.com/go-logr/logr v0.1.0
 	github.com/go-logr/zapr v0.1.0
@@ -19,15 +19,20 @@ require (
 	github.com/onsi/gomega v1.10.1
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.3.0
+	github.com/prometheus/common v0.7.0
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/sparrc/go-ping v0.0.0-20190613174326-4e5b6552494c
 	github.com/spf13/cobra v0.0.5
+	golang.org/x/crypto v0.0.0-20200317142112-1b76d66859c6
 	golang.org/x/net v0.0.0-20200520004742-59133d7f0dd7
+	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
 	gopkg.in/flanksource/yaml.v3 v3.1.1
+	gopkg.in/square/go-jose.v2 v2.4.0
 	helm.sh/helm/v3 v3.1.2
 	k8s.io/api v0.17.7
 	k8s.io/apimachinery v0.17.7
+	k8s.io/cli-runtime v0.17.2
 	k8s.io/client-go v11.0.0+incompatible
 	sigs.k8s.io/controller-runtime v0.5.7
 	sigs.k8s.io/yaml v1.1.0
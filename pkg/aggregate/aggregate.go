@@ -23,7 +23,9 @@ var Servers []string
 type AggregateCheck struct {
 	Type        string                       `json:"type"`
 	Name        string                       `json:"name"`
+	Canary      string                       `json:"canary,omitempty"`
 	Description string                       `json:"description"`
+	Labels      map[string]string            `json:"labels,omitempty"`
 	Statuses    map[string][]pkg.CheckStatus `json:"checkStatuses"`
 }
 
@@ -33,10 +35,13 @@ func (c AggregateChecks) Len() int {
 	return len(c)
 }
 func (c AggregateChecks) Less(i, j int) bool {
-	if c[i].Type == c[j].Type {
-		return c[i].Name < c[j].Name
+	if c[i].Type != c[j].Type {
+		return c[i].Type < c[j].Type
 	}
-	return c[i].Type < c[j].Type
+	if c[i].Canary != c[j].Canary {
+		return c[i].Canary < c[j].Canary
+	}
+	return c[i].Name < c[j].Name
 }
 func (c AggregateChecks) Swap(i, j int) {
 	c[i], c[j] = c[j], c[i]
@@ -78,7 +83,9 @@ func Handler(w nethttp.ResponseWriter, req *nethttp.Request) {
 		aggregateData[id] = &AggregateCheck{
 			Name:        c.Name,
 			Type:        c.Type,
+			Canary:      c.Canary,
 			Description: c.Description,
+			Labels:      c.Labels,
 			Statuses: map[string][]pkg.CheckStatus{
 				api.ServerName: c.Statuses,
 			},
@@ -103,8 +110,11 @@ func Handler(w nethttp.ResponseWriter, req *nethttp.Request) {
 				ac.Statuses[apiResponse.ServerName] = c.Statuses
 			} else {
 				aggregateData[id] = &AggregateCheck{
-					Name: c.Name,
-					Type: c.Type,
+					Name:        c.Name,
+					Type:        c.Type,
+					Canary:      c.Canary,
+					Description: c.Description,
+					Labels:      c.Labels,
 					Statuses: map[string][]pkg.CheckStatus{
 						apiResponse.ServerName: c.Statuses,
 					},
@@ -22,6 +22,36 @@ type WithType interface {
 	GetType() string
 }
 
+// Named is implemented by checks with an optional name identifying them amongst the checks of
+// their type, see CheckName
+type Named interface {
+	GetName() string
+}
+
+// Labelled is implemented by checks with arbitrary labels, which are shown with their results and
+// matched by silences
+type Labelled interface {
+	GetLabels() map[string]string
+}
+
+// WithDependencies is implemented by checks that are only run once the checks they depend on pass.
+// Dependencies are referenced by <type>/<name> within the same canary, or <namespace>/<canary>/<type>/<name>
+type WithDependencies interface {
+	GetDependsOn() []string
+}
+
+// WithMaintenance is implemented by checks with their own maintenance windows, in addition to the
+// windows of their canary
+type WithMaintenance interface {
+	GetMaintenance() []v1.MaintenanceWindow
+}
+
+// WithTimeout is implemented by checks with a timeout in seconds after which they are reported as
+// timed out, 0 defaults to the interval of the canary
+type WithTimeout interface {
+	GetTimeout() int64
+}
+
 type Endpoint struct {
 	String string
 }
@@ -45,18 +75,25 @@ func (t *JSONTime) UnmarshalJSON(b []byte) error {
 }
 
 type CheckStatus struct {
-	Status   bool     `json:"status"`
-	Invalid  bool     `json:"invalid"`
-	Time     JSONTime `json:"time"`
-	Duration int      `json:"duration"`
-	Message  string   `json:"message"`
+	Status      bool     `json:"status"`
+	Invalid     bool     `json:"invalid"`
+	TimedOut    bool     `json:"timedOut,omitempty"`
+	Flapping    bool     `json:"flapping,omitempty"`
+	BlockedBy   string   `json:"blockedBy,omitempty"`
+	Maintenance bool     `json:"maintenance,omitempty"`
+	Time        JSONTime `json:"time"`
+	Duration    int      `json:"duration"`
+	Message     string   `json:"message"`
 }
 
 type Check struct {
-	Type        string        `json:"type"`
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	Statuses    []CheckStatus `json:"checkStatuses"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Canary is the namespace/name of the canary the check belongs to, if any
+	Canary      string            `json:"canary,omitempty"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Statuses    []CheckStatus     `json:"checkStatuses"`
 }
 
 type Checks []Check
@@ -65,17 +102,20 @@ func (c Checks) Len() int {
 	return len(c)
 }
 func (c Checks) Less(i, j int) bool {
-	if c[i].Type == c[j].Type {
-		return c[i].Name < c[j].Name
+	if c[i].Type != c[j].Type {
+		return c[i].Type < c[j].Type
+	}
+	if c[i].Canary != c[j].Canary {
+		return c[i].Canary < c[j].Canary
 	}
-	return c[i].Type < c[j].Type
+	return c[i].Name < c[j].Name
 }
 func (c Checks) Swap(i, j int) {
 	c[i], c[j] = c[j], c[i]
 }
 
 func (c Check) ToString() string {
-	return fmt.Sprintf("%s;%s", c.Type, c.Name)
+	return fmt.Sprintf("%s;%s;%s", c.Canary, c.Type, c.Name)
 }
 
 func (c Check) GetDescription() string {
@@ -83,21 +123,40 @@ func (c Check) GetDescription() string {
 }
 
 type Config struct {
-	HTTP       []v1.HTTPCheck       `yaml:"http,omitempty" json:"http,omitempty"`
-	DNS        []v1.DNSCheck        `yaml:"dns,omitempty" json:"dns,omitempty"`
-	DockerPull []v1.DockerPullCheck `yaml:"docker,omitempty" json:"docker,omitempty"`
-	DockerPush []v1.DockerPushCheck `yaml:"dockerPush,omitempty" json:"dockerPush,omitempty"`
-	S3         []v1.S3Check         `yaml:"s3,omitempty" json:"s3,omitempty"`
-	S3Bucket   []v1.S3BucketCheck   `yaml:"s3Bucket,omitempty" json:"s3Bucket,omitempty"`
-	TCP        []v1.TCPCheck        `yaml:"tcp,omitempty" json:"tcp,omitempty"`
-	Pod        []v1.PodCheck        `yaml:"pod,omitempty" json:"pod,omitempty"`
-	LDAP       []v1.LDAPCheck       `yaml:"ldap,omitempty" json:"ldap,omitempty"`
-	SSL        []v1.SSLCheck        `yaml:"ssl,omitempty" json:"ssl,omitempty"`
-	ICMP       []v1.ICMPCheck       `yaml:"icmp,omitempty" json:"icmp,omitempty"`
-	Postgres   []v1.PostgresCheck   `yaml:"postgres,omitempty" json:"postgres,omitempty"`
-	Helm       []v1.HelmCheck       `yaml:"helm,omitempty" json:"helm,omitempty"`
-	Namespace  []v1.NamespaceCheck  `yaml:"namespace,omitempty" json:"namespace,omitempty"`
-	Interval   metav1.Duration      `yaml:"-" json:"interval,omitempty"`
+	HTTP             []v1.HTTPCheck           `yaml:"http,omitempty" json:"http,omitempty"`
+	DNS              []v1.DNSCheck            `yaml:"dns,omitempty" json:"dns,omitempty"`
+	DockerPull       []v1.DockerPullCheck     `yaml:"docker,omitempty" json:"docker,omitempty"`
+	DockerPush       []v1.DockerPushCheck     `yaml:"dockerPush,omitempty" json:"dockerPush,omitempty"`
+	S3               []v1.S3Check             `yaml:"s3,omitempty" json:"s3,omitempty"`
+	S3Bucket         []v1.S3BucketCheck       `yaml:"s3Bucket,omitempty" json:"s3Bucket,omitempty"`
+	TCP              []v1.TCPCheck            `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+	Pod              []v1.PodCheck            `yaml:"pod,omitempty" json:"pod,omitempty"`
+	LDAP             []v1.LDAPCheck           `yaml:"ldap,omitempty" json:"ldap,omitempty"`
+	SSL              []v1.SSLCheck            `yaml:"ssl,omitempty" json:"ssl,omitempty"`
+	ICMP             []v1.ICMPCheck           `yaml:"icmp,omitempty" json:"icmp,omitempty"`
+	Postgres         []v1.PostgresCheck       `yaml:"postgres,omitempty" json:"postgres,omitempty"`
+	Helm             []v1.HelmCheck           `yaml:"helm,omitempty" json:"helm,omitempty"`
+	Namespace        []v1.NamespaceCheck      `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	SSH              []v1.SSHCheck            `yaml:"ssh,omitempty" json:"ssh,omitempty"`
+	Prometheus       []v1.PrometheusCheck     `yaml:"prometheus,omitempty" json:"prometheus,omitempty"`
+	Kubernetes       []v1.KubernetesCheck     `yaml:"kubernetes,omitempty" json:"kubernetes,omitempty"`
+	Job              []v1.JobCheck            `yaml:"job,omitempty" json:"job,omitempty"`
+	Connectivity     []v1.ConnectivityCheck   `yaml:"connectivity,omitempty" json:"connectivity,omitempty"`
+	Volume           []v1.VolumeCheck         `yaml:"volume,omitempty" json:"volume,omitempty"`
+	Swift            []v1.SwiftCheck          `yaml:"swift,omitempty" json:"swift,omitempty"`
+	SwiftContainer   []v1.SwiftContainerCheck `yaml:"swiftContainer,omitempty" json:"swiftContainer,omitempty"`
+	AzureBlob        []v1.AzureBlobCheck      `yaml:"azureBlob,omitempty" json:"azureBlob,omitempty"`
+	AzureContainer   []v1.AzureContainerCheck `yaml:"azureContainer,omitempty" json:"azureContainer,omitempty"`
+	Filesystem       []v1.FilesystemCheck     `yaml:"filesystem,omitempty" json:"filesystem,omitempty"`
+	Certificate      []v1.CertificateCheck    `yaml:"certificate,omitempty" json:"certificate,omitempty"`
+	OIDC             []v1.OIDCCheck           `yaml:"oidc,omitempty" json:"oidc,omitempty"`
+	Interval         metav1.Duration          `yaml:"-" json:"interval,omitempty"`
+	Schedule         string                   `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	Schedules        map[string]string        `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+	Retries          int                      `yaml:"retries,omitempty" json:"retries,omitempty"`
+	FailureThreshold int                      `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	SuccessThreshold int                      `yaml:"successThreshold,omitempty" json:"successThreshold,omitempty"`
+	Maintenance      []v1.MaintenanceWindow   `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
 }
 
 type Checker interface {
@@ -117,12 +176,48 @@ type GenericCheck interface {
 	Endpointer
 	Describable
 	WithType
+	Named
+	Labelled
+	WithDependencies
+	WithMaintenance
+}
+
+// CheckName returns the name identifying check amongst the checks of its type in a canary, which
+// defaults to its description or endpoint
+func CheckName(check GenericCheck) string {
+	if name := check.GetName(); name != "" {
+		return name
+	}
+	if description := check.GetDescription(); description != "" {
+		return description
+	}
+	return check.GetEndpoint()
+}
+
+// CheckKey returns a key identifying check, canary is the namespace/name of the canary running the
+// check or empty
+func CheckKey(canary string, check GenericCheck) string {
+	key := fmt.Sprintf("%s/%s", check.GetType(), CheckName(check))
+	if canary != "" {
+		return canary + "/" + key
+	}
+	return key
 }
 
 type CheckResult struct {
-	Pass        bool
-	Invalid     bool
+	Pass     bool
+	Invalid  bool
+	TimedOut bool
+	// Flapping is true if the check changes between passing and failing too often
+	Flapping bool
+	// BlockedBy is the key of the failing dependency that caused the check not to run, see CheckKey
+	BlockedBy string
+	// Maintenance is true if the check ran during a maintenance window or silence
+	Maintenance bool
 	Duration    int64
+	// Start and End are the times the check started and completed running
+	Start       time.Time
+	End         time.Time
 	Description string
 	Message     string
 	Metrics     []Metric
@@ -136,6 +231,12 @@ func (c CheckResult) String() string {
 	} else {
 		if c.Invalid {
 			return fmt.Sprintf("[%s] <%s> [%s] %s duration=%d %s %s", console.Redf("FAIL"), console.Redf("INVALID"), c.Check.GetType(), c.Check.GetEndpoint(), c.Duration, c.Metrics, c.Message)
+		} else if c.Maintenance {
+			return fmt.Sprintf("[%s] <%s> [%s] %s duration=%d %s %s", console.Redf("FAIL"), console.Greenf("MAINTENANCE"), c.Check.GetType(), c.Check.GetEndpoint(), c.Duration, c.Metrics, c.Message)
+		} else if c.BlockedBy != "" {
+			return fmt.Sprintf("[%s] <%s> [%s] %s duration=%d %s %s", console.Redf("FAIL"), console.Redf("BLOCKED"), c.Check.GetType(), c.Check.GetEndpoint(), c.Duration, c.Metrics, c.Message)
+		} else if c.TimedOut {
+			return fmt.Sprintf("[%s] <%s> [%s] %s duration=%d %s %s", console.Redf("FAIL"), console.Redf("TIMEOUT"), c.Check.GetType(), c.Check.GetEndpoint(), c.Duration, c.Metrics, c.Message)
 		} else {
 			return fmt.Sprintf("[%s] <%s> [%s] %s duration=%d %s %s", console.Redf("FAIL"), console.Greenf("VALID"), c.Check.GetType(), c.Check.GetEndpoint(), c.Duration, c.Metrics, c.Message)
 		}
@@ -1,7 +1,6 @@
 package cache
 
 import (
-	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -21,15 +20,16 @@ var Cache = &cache{
 	Checks: make(map[string]pkg.Check),
 }
 
-func AddCheck(name string, result *pkg.CheckResult) *pkg.Check {
-	return Cache.AddCheck(name, result)
+// AddCheck records the result of a check of canary, which is empty for checks that are not run by a canary
+func AddCheck(canary string, result *pkg.CheckResult) *pkg.Check {
+	return Cache.AddCheck(canary, result)
 }
 
 func GetChecks() pkg.Checks {
 	return Cache.GetChecks()
 }
 
-func (c *cache) AddCheck(name string, result *pkg.CheckResult) *pkg.Check {
+func (c *cache) AddCheck(canary string, result *pkg.CheckResult) *pkg.Check {
 	if result == nil || result.Check == nil {
 		logger.Warnf("result with no check found: %+v", result)
 		return nil
@@ -37,26 +37,32 @@ func (c *cache) AddCheck(name string, result *pkg.CheckResult) *pkg.Check {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	description := result.Check.GetDescription()
-	if description == "" {
-		description = result.Check.GetEndpoint()
+	end := result.End
+	if end.IsZero() {
+		end = time.Now()
 	}
 	check := pkg.Check{
 		Type:        result.Check.GetType(),
-		Name:        name,
-		Description: description,
+		Name:        pkg.CheckName(result.Check),
+		Canary:      canary,
+		Description: result.Check.GetDescription(),
+		Labels:      result.Check.GetLabels(),
 		Statuses: []pkg.CheckStatus{
 			{
-				Status:   result.Pass,
-				Invalid:  result.Invalid,
-				Duration: int(result.Duration),
-				Time:     pkg.JSONTime(time.Now().UTC()),
-				Message:  result.Message,
+				Status:      result.Pass,
+				Invalid:     result.Invalid,
+				TimedOut:    result.TimedOut,
+				Flapping:    result.Flapping,
+				BlockedBy:   result.BlockedBy,
+				Maintenance: result.Maintenance,
+				Duration:    int(result.Duration),
+				Time:        pkg.JSONTime(end.UTC()),
+				Message:     result.Message,
 			},
 		},
 	}
 
-	key := fmt.Sprintf("%s/%s", check.Type, check.Name)
+	key := check.ToString()
 	lastCheck, found := c.Checks[key]
 	if found {
 		check.Statuses = append(check.Statuses, lastCheck.Statuses...)
@@ -69,6 +75,25 @@ func (c *cache) AddCheck(name string, result *pkg.CheckResult) *pkg.Check {
 	return &lastCheck
 }
 
+// Prune removes the checks of canary that are not in checks, keeping the history of the remaining ones
+func Prune(canary string, checks []pkg.GenericCheck) {
+	Cache.Prune(canary, checks)
+}
+
+func (c *cache) Prune(canary string, checks []pkg.GenericCheck) {
+	keep := make(map[string]bool)
+	for _, check := range checks {
+		keep[pkg.Check{Canary: canary, Type: check.GetType(), Name: pkg.CheckName(check)}.ToString()] = true
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key, check := range c.Checks {
+		if check.Canary == canary && !keep[key] {
+			delete(c.Checks, key)
+		}
+	}
+}
+
 func (s *cache) GetChecks() pkg.Checks {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	v1 "github.com/flanksource/canary-checker/api/v1"
@@ -15,16 +17,94 @@ import (
 
 // ParseConfig : Read config file
 func ParseConfig(configfile string) v1.CanarySpec {
+	config, err := LoadConfig(configfile)
+	if err != nil {
+		logger.Fatalf("error: %v", err)
+	}
+	return config
+}
+
+// LoadConfig reads configfile and applies its templates, returning an error if it cannot be read or
+// parsed
+func LoadConfig(configfile string) (v1.CanarySpec, error) {
 	config := v1.CanarySpec{}
 	data, err := ioutil.ReadFile(configfile)
 	if err != nil {
-		logger.Infof("yamlFile.Get err   #%v ", err)
+		return config, err
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("%s: %v", configfile, err)
+	}
+	return ApplyTemplates(config), nil
+}
+
+// ConfigFiles returns the config files in paths, replacing directories with the YAML files they
+// contain in alphabetical order
+func ConfigFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		var dirFiles []string
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			dirFiles = append(dirFiles, matches...)
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+// AllChecks returns every check of spec
+func AllChecks(spec v1.CanarySpec) []GenericCheck {
+	var checks []GenericCheck
+	value := reflect.ValueOf(spec)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() != reflect.Slice {
+			continue
+		}
+		for j := 0; j < field.Len(); j++ {
+			if check, ok := field.Index(j).Interface().(GenericCheck); ok {
+				checks = append(checks, check)
+			}
+		}
 	}
-	yamlerr := yaml.Unmarshal([]byte(data), &config)
-	if yamlerr != nil {
-		logger.Fatalf("error: %v", yamlerr)
+	return checks
+}
+
+// WithChecks returns spec with only the checks whose key is in keys, keeping the other settings of spec
+func WithChecks(spec v1.CanarySpec, keys []string) v1.CanarySpec {
+	include := make(map[string]bool)
+	for _, key := range keys {
+		include[key] = true
+	}
+	genericCheck := reflect.TypeOf((*GenericCheck)(nil)).Elem()
+	value := reflect.ValueOf(&spec).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() != reflect.Slice || !field.Type().Elem().Implements(genericCheck) {
+			continue
+		}
+		checks := reflect.MakeSlice(field.Type(), 0, 1)
+		for j := 0; j < field.Len(); j++ {
+			if include[CheckKey("", field.Index(j).Interface().(GenericCheck))] {
+				checks = reflect.Append(checks, field.Index(j))
+			}
+		}
+		field.Set(checks)
 	}
-	return ApplyTemplates(config)
+	return spec
 }
 
 type StructTemplater struct {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/flanksource/canary-checker/checks"
 	"github.com/flanksource/canary-checker/pkg"
 	"github.com/flanksource/canary-checker/pkg/cache"
+	"github.com/flanksource/canary-checker/pkg/health"
 	"github.com/flanksource/canary-checker/pkg/metrics"
 	"github.com/go-logr/logr"
 	"github.com/mitchellh/reflectwalk"
@@ -64,25 +66,53 @@ func (r *CanaryReconciler) Report(key types.NamespacedName, results []*pkg.Check
 
 	check.Status.LastCheck = &metav1.Time{Time: time.Now()}
 	transitioned := false
-	pass := true
+	statuses := make(map[string]checkTypeStatus)
+	canary := fmt.Sprintf("%s/%s", key.Namespace, key.Name)
 	for _, result := range results {
-		lastResult := cache.AddCheck(fmt.Sprintf("%s/%s", key.Namespace, key.Name), result)
+		transition := health.Update(pkg.CheckKey(canary, result.Check), check.Spec, result)
+		lastResult := cache.AddCheck(canary, result)
 		metrics.Record(check.Namespace, check.Name, result)
 		if lastResult != nil && len(lastResult.Statuses) > 0 && (lastResult.Statuses[0].Status != result.Pass) {
 			transitioned = true
 		}
-		if !result.Pass {
-			r.Events.Event(&check, corev1.EventTypeWarning, "Failed", fmt.Sprintf("%s-%s: %s", result.Check.GetType(), result.Check.GetEndpoint(), result.Message))
+		if transition.StartedFlapping {
+			r.Events.Event(&check, corev1.EventTypeWarning, "Flapping", fmt.Sprintf("%s-%s: changes state too often", result.Check.GetType(), pkg.CheckName(result.Check)))
+		}
+		// flapping checks only report when they start flapping, blocked checks are reported by the
+		// failing dependency and checks in maintenance are expected to fail
+		if !result.Pass && !result.Flapping && result.BlockedBy == "" && !result.Maintenance {
+			r.Events.Event(&check, corev1.EventTypeWarning, "Failed", fmt.Sprintf("%s-%s: %s", result.Check.GetType(), pkg.CheckName(result.Check), result.Message))
 		}
 
 		if transitioned {
 			check.Status.LastTransitionedTime = &metav1.Time{Time: time.Now()}
 		}
-		pass = pass && result.Pass
+		// failed checks take precedence over blocked checks in the status of their type, checks in
+		// maintenance are only reported if all checks of their type are in maintenance
+		status, found := statuses[result.Check.GetType()]
+		if result.Maintenance {
+			if !found {
+				statuses[result.Check.GetType()] = checkTypeStatus{Pass: true, Maintenance: true}
+			}
+		} else if !found || status.Pass || (status.BlockedBy != "" && !result.Pass && result.BlockedBy == "") {
+			statuses[result.Check.GetType()] = checkTypeStatus{Pass: result.Pass, BlockedBy: result.BlockedBy, Message: result.Message}
+		}
 	}
-	if pass {
+	status := checkStatuses.Update(key, statuses)
+	check.Status.BlockedBy = status.BlockedBy
+	check.Status.Message = nil
+	uptime, latency := uptimes.Update(key, results)
+	check.Status.Uptime1H = uptime
+	check.Status.Latency1H = latency
+	switch {
+	case status.Maintenance:
+		check.Status.Status = &v1.Maintenance
+	case status.Pass:
 		check.Status.Status = &v1.Passed
-	} else {
+	case status.BlockedBy != "":
+		check.Status.Status = &v1.Blocked
+		check.Status.Message = &status.Message
+	default:
 		check.Status.Status = &v1.Failed
 	}
 	r.Patch(check)
@@ -96,9 +126,123 @@ func (r *CanaryReconciler) Patch(canary v1.Canary) {
 	}
 }
 
+// checkStatuses tracks whether the latest checks of every type passed for each canary, as check types
+// can run on different schedules and only report their own results
+var checkStatuses = &canaryCheckStatuses{canaries: make(map[types.NamespacedName]map[string]checkTypeStatus)}
+
+// checkTypeStatus is the status of the checks of a type, BlockedBy and Message are the root cause of
+// checks blocked by a failing dependency and Maintenance is true if all checks are in maintenance
+type checkTypeStatus struct {
+	Pass        bool
+	BlockedBy   string
+	Message     string
+	Maintenance bool
+}
+
+type canaryCheckStatuses struct {
+	canaries map[types.NamespacedName]map[string]checkTypeStatus
+	mtx      sync.Mutex
+}
+
+// Update records the statuses of the checks of every type and returns the status of the canary, which
+// passes if the latest checks of all types have passed, is only blocked if no check has failed and is
+// in maintenance if the checks of all types are in maintenance
+func (s *canaryCheckStatuses) Update(key types.NamespacedName, updated map[string]checkTypeStatus) checkTypeStatus {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	statuses, found := s.canaries[key]
+	if !found {
+		statuses = make(map[string]checkTypeStatus)
+		s.canaries[key] = statuses
+	}
+	for checkType, status := range updated {
+		statuses[checkType] = status
+	}
+
+	var checkTypes []string
+	for checkType := range statuses {
+		checkTypes = append(checkTypes, checkType)
+	}
+	sort.Strings(checkTypes)
+	result := checkTypeStatus{Pass: true, Maintenance: len(checkTypes) > 0}
+	for _, checkType := range checkTypes {
+		status := statuses[checkType]
+		if status.Pass {
+			result.Maintenance = result.Maintenance && status.Maintenance
+			continue
+		}
+		if status.BlockedBy == "" {
+			return checkTypeStatus{}
+		}
+		if result.Pass {
+			result = status
+		}
+	}
+	return result
+}
+
+// Reset forgets the statuses of a canary once its checks are rescheduled
+func (s *canaryCheckStatuses) Reset(key types.NamespacedName) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.canaries, key)
+}
+
+// uptimes tracks the results of the checks of each canary over the last hour
+var uptimes = &canaryUptimes{canaries: make(map[types.NamespacedName][]uptimeSample)}
+
+// UptimeWindow is the period over which Uptime1H and Latency1H are calculated
+var UptimeWindow = time.Hour
+
+type uptimeSample struct {
+	time     time.Time
+	pass     bool
+	duration int64
+}
+
+type canaryUptimes struct {
+	canaries map[types.NamespacedName][]uptimeSample
+	mtx      sync.Mutex
+}
+
+// Update records results and returns the percentage of checks that passed and their average duration
+// within the window. Checks in maintenance and blocked checks are not counted as they say nothing about
+// the availability of what they check
+func (u *canaryUptimes) Update(key types.NamespacedName, results []*pkg.CheckResult) (string, int64) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	now := time.Now()
+	samples := u.canaries[key]
+	for _, result := range results {
+		if result.Maintenance || result.BlockedBy != "" {
+			continue
+		}
+		samples = append(samples, uptimeSample{time: now, pass: result.Pass, duration: result.Duration})
+	}
+	cutoff := now.Add(-UptimeWindow)
+	for len(samples) > 0 && samples[0].time.Before(cutoff) {
+		samples = samples[1:]
+	}
+	u.canaries[key] = samples
+	if len(samples) == 0 {
+		return "", 0
+	}
+	passed := 0
+	var duration int64
+	for _, sample := range samples {
+		if sample.pass {
+			passed++
+		}
+		duration += sample.duration
+	}
+	return fmt.Sprintf("%.1f%%", float64(passed)*100/float64(len(samples))), duration / int64(len(samples))
+}
+
 type CanaryJob struct {
-	Client CanaryReconciler
-	Check  v1.Canary
+	Client   CanaryReconciler
+	Check    v1.Canary
+	Schedule string
+	Checkers []checks.Checker
 	logr.Logger
 }
 
@@ -113,12 +257,13 @@ func (c CanaryJob) Run() {
 		c.Error(err, "Failed to load secrets")
 		return
 	}
-	c.Info("Starting")
-
-	var results []*pkg.CheckResult
-	for _, check := range checks.All {
-		results = append(results, check.Run(spec)...)
+	// checks waiting for pods or jobs use the interval as their deadline
+	if interval, err := checks.ScheduleInterval(c.Schedule); err == nil {
+		spec.Interval = int64(interval.Seconds())
 	}
+	c.Info("Starting", "schedule", c.Schedule)
+
+	results := checks.RunChecks(context.Background(), c.GetNamespacedName().String(), c.Checkers, spec)
 
 	c.Client.Report(c.GetNamespacedName(), results)
 
@@ -168,10 +313,40 @@ func LoadSecrets(client CanaryReconciler, canary v1.Canary) (v1.CanarySpec, erro
 	if err := reflectwalk.Walk(val, StructTemplater{Values: values}); err != nil {
 		return canary.Spec, err
 	}
+	if err := reflectwalk.Walk(val, VarSourceResolver{Client: client.Kubernetes, Canary: &canary}); err != nil {
+		return canary.Spec, err
+	}
 	return *val, nil
 
 }
 
+// VarSourceResolver replaces every VarSource field of a check with its resolved value
+type VarSourceResolver struct {
+	Client kubernetes.Interface
+	Canary *v1.Canary
+}
+
+func (w VarSourceResolver) Struct(v reflect.Value) error {
+	if !v.CanSet() || v.Type() != reflect.TypeOf(v1.VarSource{}) {
+		return nil
+	}
+	source := v.Interface().(v1.VarSource)
+	if source.IsResolved() {
+		return nil
+	}
+	val, err := v1.GetEnvVarRefValue(w.Client, w.Canary.Namespace, &source, w.Canary)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(v1.VarSource{Value: val}))
+	return nil
+}
+
+// this func is required to fulfil the reflectwalk.StructWalker interface
+func (w VarSourceResolver) StructField(f reflect.StructField, v reflect.Value) error {
+	return nil
+}
+
 // track the canaries that have already been scheduled
 var observed = sync.Map{}
 
@@ -206,22 +381,33 @@ func (r *CanaryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		if entry.Job.(CanaryJob).GetNamespacedName() == req.NamespacedName {
 			logger.Info("unscheduled", "id", entry.ID)
 			r.Cron.Remove(entry.ID)
-			break
 		}
 	}
+	checkStatuses.Reset(req.NamespacedName)
+
+	if err := checks.Validate(req.NamespacedName.String(), check.Spec); err != nil {
+		// invalid canaries are not scheduled until they are fixed
+		logger.Error(err, "invalid canary")
+		message := err.Error()
+		check.Status.Status = &v1.Invalid
+		check.Status.Message = &message
+		check.Status.ObservedGeneration = check.Generation
+		r.Patch(check)
+		return ctrl.Result{}, nil
+	}
 
-	if check.Spec.Interval > 0 {
-		job := CanaryJob{Client: *r, Check: check, Logger: logger}
+	for schedule, checkers := range checks.Scheduled(check.Spec) {
+		job := CanaryJob{Client: *r, Check: check, Schedule: schedule, Checkers: checkers, Logger: logger}
+		id, err := r.Cron.AddJob(schedule, job)
+		if err != nil {
+			logger.Error(err, "failed to schedule job", "schedule", schedule)
+			continue
+		}
+		logger.Info("scheduled", "id", id, "schedule", schedule, "next", r.Cron.Entry(id).Next)
 		if !run {
 			// check each job on startup
 			go job.Run()
 		}
-		id, err := r.Cron.AddJob(fmt.Sprintf("@every %ds", check.Spec.Interval), job)
-		if err != nil {
-			logger.Error(err, "failed to schedule job", "schedule", check.Spec.Interval)
-		} else {
-			logger.Info("scheduled", "id", id, "next", r.Cron.Entry(id).Next)
-		}
 	}
 
 	check.Status.ObservedGeneration = check.Generation
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+)
+
+var (
+	// FailureThreshold is the default number of consecutive failures before a check is reported as failed
+	FailureThreshold = 1
+	// SuccessThreshold is the default number of consecutive successes before a failed check is reported as passing
+	SuccessThreshold = 1
+	// FlapWindow is the number of latest results in which state changes are counted
+	FlapWindow = 10
+	// FlapThreshold is the number of state changes within the window after which a check is flapping
+	FlapThreshold = 5
+)
+
+// Transition describes how the reported state of a check changed with its latest result
+type Transition struct {
+	// Changed is true if the check is reported as passing or failing for the first time or the
+	// reported state has changed
+	Changed bool
+	// StartedFlapping is true if the check started flapping with the latest result
+	StartedFlapping bool
+}
+
+type state struct {
+	pass      bool
+	failures  int
+	successes int
+	history   []bool
+	flapping  bool
+	message   string
+	blockedBy string
+}
+
+var states = struct {
+	sync.Mutex
+	checks map[string]*state
+}{checks: make(map[string]*state)}
+
+// Update records the latest result of the check identified by key and replaces its pass status with
+// the reported state, which only changes once the failure or success threshold of spec is reached.
+// Results of invalid checks and of checks blocked by a dependency are reported as they are and results
+// of checks in maintenance do not change the reported state
+func Update(key string, spec v1.CanarySpec, result *pkg.CheckResult) Transition {
+	if result == nil {
+		return Transition{}
+	}
+	states.Lock()
+	defer states.Unlock()
+
+	s, found := states.checks[key]
+	if !found {
+		// checks are assumed to pass until they reach the failure threshold
+		s = &state{pass: true}
+		states.checks[key] = s
+	}
+	if result.Maintenance {
+		// checks in maintenance are expected to fail and do not count towards the thresholds
+		return Transition{}
+	}
+	if result.BlockedBy != "" {
+		// blocked checks were not run and do not count towards the thresholds
+		changed := s.blockedBy != result.BlockedBy
+		s.blockedBy = result.BlockedBy
+		s.message = result.Message
+		return Transition{Changed: changed}
+	}
+	s.blockedBy = ""
+
+	if result.Pass {
+		s.successes++
+		s.failures = 0
+	} else {
+		s.failures++
+		s.successes = 0
+	}
+
+	transition := Transition{Changed: !found}
+	failureThreshold := threshold(spec.FailureThreshold, FailureThreshold)
+	successThreshold := threshold(spec.SuccessThreshold, SuccessThreshold)
+	switch {
+	case s.pass && (result.Invalid || s.failures >= failureThreshold):
+		s.pass = false
+		transition.Changed = true
+	case !s.pass && s.successes >= successThreshold:
+		s.pass = true
+		transition.Changed = true
+	case s.pass && !result.Pass:
+		result.Message = fmt.Sprintf("%s (failure %d of %d)", result.Message, s.failures, failureThreshold)
+	case !s.pass && result.Pass:
+		result.Message = fmt.Sprintf("%s (success %d of %d)", result.Message, s.successes, successThreshold)
+	}
+	result.Pass = s.pass
+	s.message = result.Message
+
+	s.history = append(s.history, s.failures == 0)
+	if len(s.history) > FlapWindow {
+		s.history = s.history[len(s.history)-FlapWindow:]
+	}
+	flapping := changes(s.history) >= FlapThreshold
+	transition.StartedFlapping = flapping && !s.flapping
+	s.flapping = flapping
+	result.Flapping = flapping
+	return transition
+}
+
+// Status returns whether the check identified by key is reported as passing and otherwise the key of
+// the check that is the root cause of the failure with its message. found is false if the check has
+// not reported yet
+func Status(key string) (pass bool, rootCause string, message string, found bool) {
+	states.Lock()
+	defer states.Unlock()
+	s, found := states.checks[key]
+	if !found {
+		return false, "", "", false
+	}
+	if s.blockedBy != "" {
+		return false, s.blockedBy, s.message, true
+	}
+	return s.pass, key, s.message, true
+}
+
+// Remove forgets the state of the check identified by key once it is no longer run
+func Remove(key string) {
+	states.Lock()
+	defer states.Unlock()
+	delete(states.checks, key)
+}
+
+func threshold(value, defaultValue int) int {
+	if value > 0 {
+		return value
+	}
+	if defaultValue > 0 {
+		return defaultValue
+	}
+	return 1
+}
+
+// changes returns the number of times the results in history changed between passing and failing
+func changes(history []bool) int {
+	count := 0
+	for i := 1; i < len(history); i++ {
+		if history[i] != history[i-1] {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,121 @@
+package health
+
+import (
+	"testing"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+)
+
+// update records results written as one character per result: P passed, F failed, I invalid and M failed
+// in maintenance, returning the reported states in the same format
+func update(key string, spec v1.CanarySpec, results string) (reported string, last Transition, flapping bool) {
+	for _, r := range results {
+		result := &pkg.CheckResult{
+			Pass:        r == 'P',
+			Invalid:     r == 'I',
+			Maintenance: r == 'M',
+		}
+		last = Update(key, spec, result)
+		flapping = result.Flapping
+		if result.Maintenance {
+			reported += "M"
+		} else if result.Pass {
+			reported += "P"
+		} else {
+			reported += "F"
+		}
+	}
+	return reported, last, flapping
+}
+
+func TestUpdateThresholds(t *testing.T) {
+	tests := []struct {
+		name             string
+		failureThreshold int
+		successThreshold int
+		results          string
+		want             string
+	}{
+		{name: "default thresholds", results: "PFFP", want: "PFFP"},
+		{name: "failure threshold", failureThreshold: 3, results: "FFFF", want: "PPFF"},
+		{name: "failures reset by a success", failureThreshold: 2, results: "FPFPF", want: "PPPPP"},
+		{name: "success threshold", successThreshold: 2, results: "FPPP", want: "FFPP"},
+		{name: "successes reset by a failure", successThreshold: 2, results: "FPFPP", want: "FFFFP"},
+		{name: "invalid fails immediately", failureThreshold: 3, results: "PI", want: "PF"},
+		{name: "maintenance does not count", failureThreshold: 2, results: "FMMF", want: "PMMF"},
+		{name: "maintenance keeps the failed state", results: "FMP", want: "FMP"},
+	}
+	for _, tc := range tests {
+		key := "http/" + tc.name
+		spec := v1.CanarySpec{FailureThreshold: tc.failureThreshold, SuccessThreshold: tc.successThreshold}
+		if got, _, _ := update(key, spec, tc.results); got != tc.want {
+			t.Errorf("Test %s failed. Expected %s for %s, but found %s", tc.name, tc.want, tc.results, got)
+		}
+		Remove(key)
+	}
+}
+
+func TestUpdateFlapping(t *testing.T) {
+	tests := []struct {
+		name            string
+		results         string
+		flapping        bool
+		startedFlapping bool
+	}{
+		{name: "stable", results: "PPPPPPPPPP", flapping: false},
+		{name: "below threshold", results: "PFPFP", flapping: false},
+		{name: "starts flapping", results: "PFPFPF", flapping: true, startedFlapping: true},
+		{name: "keeps flapping", results: "PFPFPFP", flapping: true, startedFlapping: false},
+		{name: "changes outside the window", results: "PFPFPFFFFFFFFFFF", flapping: false},
+		{name: "changes within the window", results: "PPPPPPPFPFPF", flapping: true, startedFlapping: true},
+	}
+	for _, tc := range tests {
+		key := "http/" + tc.name
+		_, transition, flapping := update(key, v1.CanarySpec{}, tc.results)
+		if flapping != tc.flapping {
+			t.Errorf("Test %s failed. Expected flapping %v for %s, but found %v", tc.name, tc.flapping, tc.results, flapping)
+		}
+		if transition.StartedFlapping != tc.startedFlapping {
+			t.Errorf("Test %s failed. Expected started flapping %v for %s, but found %v", tc.name, tc.startedFlapping, tc.results, transition.StartedFlapping)
+		}
+		Remove(key)
+	}
+}
+
+func TestUpdateTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		results string
+		changed bool
+	}{
+		{name: "first result", results: "P", changed: true},
+		{name: "unchanged", results: "PP", changed: false},
+		{name: "failed", results: "PF", changed: true},
+		{name: "recovered", results: "PFP", changed: true},
+	}
+	for _, tc := range tests {
+		key := "http/" + tc.name
+		if _, transition, _ := update(key, v1.CanarySpec{}, tc.results); transition.Changed != tc.changed {
+			t.Errorf("Test %s failed. Expected changed %v for %s, but found %v", tc.name, tc.changed, tc.results, transition.Changed)
+		}
+		Remove(key)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	key := "http/removed"
+	spec := v1.CanarySpec{FailureThreshold: 2}
+	if got, _, _ := update(key, spec, "FF"); got != "PF" {
+		t.Fatalf("Expected PF, but found %s", got)
+	}
+	Remove(key)
+	if _, _, _, found := Status(key); found {
+		t.Errorf("Expected the state of %s to be removed", key)
+	}
+	// the failures before the check was removed no longer count
+	if got, transition, _ := update(key, spec, "F"); got != "P" || !transition.Changed {
+		t.Errorf("Expected a new passing state, but found %s (changed %v)", got, transition.Changed)
+	}
+	Remove(key)
+}
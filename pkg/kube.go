@@ -21,6 +21,7 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/flanksource/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/homedir"
@@ -29,8 +30,7 @@ import (
 )
 
 func NewK8sClient() (*kubernetes.Clientset, error) {
-	kubeConfig := GetKubeconfig()
-	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	config, err := NewK8sRestConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +43,25 @@ func NewK8sClient() (*kubernetes.Clientset, error) {
 	return clientset, nil
 }
 
+// NewDynamicClient returns a client for working with arbitrary (unstructured) resources
+func NewDynamicClient() (dynamic.Interface, error) {
+	config, err := NewK8sRestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic k8s client")
+	}
+
+	return client, nil
+}
+
+func NewK8sRestConfig() (*rest.Config, error) {
+	return clientcmd.BuildConfigFromFlags("", GetKubeconfig())
+}
+
 func GetClusterName(config *rest.Config) string {
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
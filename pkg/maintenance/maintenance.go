@@ -0,0 +1,80 @@
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/commons/logger"
+	"github.com/robfig/cron/v3"
+)
+
+// Active returns the reason check of canary is in maintenance at t, if it is covered by a maintenance
+// window of the canary or the check, or by a silence. canary is the namespace/name of the canary or empty
+func Active(canary string, spec v1.CanarySpec, check pkg.GenericCheck, t time.Time) (string, bool) {
+	windows := append([]v1.MaintenanceWindow{}, spec.Maintenance...)
+	windows = append(windows, check.GetMaintenance()...)
+	for _, window := range windows {
+		active, err := WindowActive(window, t)
+		if err != nil {
+			logger.Warnf("[%s] %s has an invalid maintenance window: %v", check.GetType(), pkg.CheckName(check), err)
+			continue
+		}
+		if active {
+			return reason(window.Reason, "maintenance window"), true
+		}
+	}
+	if silence, found := Silences.Match(canary, check, t); found {
+		return reason(silence.Reason, "silenced"), true
+	}
+	return "", false
+}
+
+// WindowActive returns true if t is within the window
+func WindowActive(window v1.MaintenanceWindow, t time.Time) (bool, error) {
+	if window.Schedule != "" {
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return false, fmt.Errorf("invalid duration %q: %v", window.Duration, err)
+		}
+		if duration <= 0 {
+			return false, fmt.Errorf("duration of schedule %s must be positive", window.Schedule)
+		}
+		schedule, err := cron.ParseStandard(window.Schedule)
+		if err != nil {
+			return false, fmt.Errorf("invalid schedule %q: %v", window.Schedule, err)
+		}
+		// the window is active if it started within the duration before t
+		return !schedule.Next(t.Add(-duration)).After(t), nil
+	}
+	if window.Start == "" && window.End == "" {
+		return false, fmt.Errorf("either a schedule or a start or end time is required")
+	}
+	if window.Start != "" {
+		start, err := time.Parse(time.RFC3339, window.Start)
+		if err != nil {
+			return false, fmt.Errorf("invalid start: %v", err)
+		}
+		if t.Before(start) {
+			return false, nil
+		}
+	}
+	if window.End != "" {
+		end, err := time.Parse(time.RFC3339, window.End)
+		if err != nil {
+			return false, fmt.Errorf("invalid end: %v", err)
+		}
+		if !t.Before(end) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func reason(reason, defaultReason string) string {
+	if reason != "" {
+		return reason
+	}
+	return defaultReason
+}
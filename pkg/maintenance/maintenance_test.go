@@ -0,0 +1,161 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestWindowActive(t *testing.T) {
+	nightly := v1.MaintenanceWindow{Schedule: "0 23 * * *", Duration: "2h"}
+	newYork := v1.MaintenanceWindow{Schedule: "CRON_TZ=America/New_York 0 23 * * *", Duration: "2h"}
+	tests := []struct {
+		name   string
+		window v1.MaintenanceWindow
+		t      time.Time
+		want   bool
+	}{
+		{name: "before a nightly window", window: nightly, t: date("2021-03-01T22:59:59Z"), want: false},
+		{name: "start of a nightly window", window: nightly, t: date("2021-03-01T23:00:00Z"), want: true},
+		{name: "nightly window after midnight", window: nightly, t: date("2021-03-02T00:30:00Z"), want: true},
+		{name: "last second of a nightly window", window: nightly, t: date("2021-03-02T00:59:59Z"), want: true},
+		{name: "end of a nightly window", window: nightly, t: date("2021-03-02T01:00:00Z"), want: false},
+		{name: "window in the location of t", window: nightly, t: date("2021-03-02T04:30:00+05:00"), want: false},
+		{name: "window in the location of t after midnight", window: nightly, t: date("2021-03-02T00:30:00+05:00"), want: true},
+		{name: "window in a cron timezone", window: newYork, t: date("2021-03-02T04:30:00Z"), want: true},
+		{name: "end of a window in a cron timezone", window: newYork, t: date("2021-03-02T06:00:00Z"), want: false},
+		{name: "utc time in a cron timezone", window: newYork, t: date("2021-03-01T23:30:00Z"), want: false},
+		{
+			name:   "start of a time range",
+			window: v1.MaintenanceWindow{Start: "2021-03-01T22:00:00Z", End: "2021-03-02T02:00:00Z"},
+			t:      date("2021-03-01T22:00:00Z"),
+			want:   true,
+		},
+		{
+			name:   "end of a time range",
+			window: v1.MaintenanceWindow{Start: "2021-03-01T22:00:00Z", End: "2021-03-02T02:00:00Z"},
+			t:      date("2021-03-02T02:00:00Z"),
+			want:   false,
+		},
+		{
+			name:   "time range with an offset",
+			window: v1.MaintenanceWindow{Start: "2021-03-01T22:00:00+02:00", End: "2021-03-02T02:00:00+02:00"},
+			t:      date("2021-03-01T23:30:00Z"),
+			want:   true,
+		},
+		{
+			name:   "open ended time range",
+			window: v1.MaintenanceWindow{Start: "2021-03-01T22:00:00Z"},
+			t:      date("2022-03-01T22:00:00Z"),
+			want:   true,
+		},
+	}
+	for _, tc := range tests {
+		got, err := WindowActive(tc.window, tc.t)
+		if err != nil {
+			t.Errorf("Test %s failed. Unexpected error: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Test %s failed. Expected %v at %s, but found %v", tc.name, tc.want, tc.t, got)
+		}
+	}
+}
+
+func TestWindowActiveInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		window v1.MaintenanceWindow
+	}{
+		{name: "empty window", window: v1.MaintenanceWindow{}},
+		{name: "schedule without duration", window: v1.MaintenanceWindow{Schedule: "0 23 * * *"}},
+		{name: "negative duration", window: v1.MaintenanceWindow{Schedule: "0 23 * * *", Duration: "-1h"}},
+		{name: "invalid schedule", window: v1.MaintenanceWindow{Schedule: "0 25 * * *", Duration: "1h"}},
+		{name: "invalid start", window: v1.MaintenanceWindow{Start: "2021-03-01"}},
+	}
+	for _, tc := range tests {
+		if _, err := WindowActive(tc.window, time.Now()); err == nil {
+			t.Errorf("Test %s failed. Expected an error", tc.name)
+		}
+	}
+}
+
+func TestSilences(t *testing.T) {
+	s := &silences{silences: make(map[string]Silence)}
+	now := time.Now()
+	check := v1.HTTPCheck{Name: "api", Endpoint: "http://api", Labels: map[string]string{"team": "payments"}}
+	added, err := s.Add(Silence{Canary: "default/http", Start: now.Add(-time.Hour), End: now.Add(time.Minute), Reason: "upgrade"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.Add(Silence{Check: "http/api", Start: now, End: now}); err == nil {
+		t.Errorf("Expected an error for a silence that ends when it starts")
+	}
+	if _, err := s.Add(Silence{End: now.Add(time.Hour)}); err == nil {
+		t.Errorf("Expected an error for a silence matching every check")
+	}
+
+	tests := []struct {
+		name   string
+		canary string
+		t      time.Time
+		want   bool
+	}{
+		{name: "before the start", canary: "default/http", t: now.Add(-2 * time.Hour), want: false},
+		{name: "within the silence", canary: "default/http", t: now, want: true},
+		{name: "other canary", canary: "default/other", t: now, want: false},
+		{name: "end of the silence", canary: "default/http", t: added.End, want: false},
+	}
+	for _, tc := range tests {
+		if _, got := s.Match(tc.canary, check, tc.t); got != tc.want {
+			t.Errorf("Test %s failed. Expected %v, but found %v", tc.name, tc.want, got)
+		}
+	}
+
+	if _, err := s.Add(Silence{Labels: map[string]string{"team": "payments"}, Start: now.Add(-time.Hour), End: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list := s.List(); len(list) != 1 || list[0].ID != added.ID {
+		t.Errorf("Expected the silence that ended to expire, but found %v", list)
+	}
+	if !s.Delete(added.ID) || s.Delete(added.ID) {
+		t.Errorf("Expected silence %s to be deleted once", added.ID)
+	}
+	if _, found := s.Match("default/http", check, now); found {
+		t.Errorf("Expected no silence after deleting it")
+	}
+}
+
+func TestActive(t *testing.T) {
+	check := v1.HTTPCheck{
+		Name:        "api",
+		Endpoint:    "http://api",
+		Maintenance: []v1.MaintenanceWindow{{Start: "2021-03-01T00:00:00Z", End: "2021-03-02T00:00:00Z", Reason: "migration"}},
+	}
+	spec := v1.CanarySpec{Maintenance: []v1.MaintenanceWindow{{Schedule: "0 23 * * *", Duration: "2h"}}}
+	tests := []struct {
+		name   string
+		t      time.Time
+		want   bool
+		reason string
+	}{
+		{name: "window of the check", t: date("2021-03-01T12:00:00Z"), want: true, reason: "migration"},
+		{name: "window of the canary", t: date("2021-03-02T23:30:00Z"), want: true, reason: "maintenance window"},
+		{name: "outside of the windows", t: date("2021-03-02T12:00:00Z"), want: false},
+	}
+	for _, tc := range tests {
+		reason, active := Active("default/http", spec, check, tc.t)
+		if active != tc.want || reason != tc.reason {
+			t.Errorf("Test %s failed. Expected %v (%s), but found %v (%s)", tc.name, tc.want, tc.reason, active, reason)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package maintenance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/flanksource/commons/logger"
+)
+
+// Silence puts the matching checks in maintenance between Start and End. Silences are kept in memory
+// and do not survive a restart
+type Silence struct {
+	ID string `json:"id"`
+	// Canary is the namespace/name of the canary to silence, checks of all canaries match if empty
+	Canary string `json:"canary,omitempty"`
+	// Check is the <type>/<name> of the check to silence, all checks of the canary match if empty
+	Check string `json:"check,omitempty"`
+	// Labels that silenced checks must have
+	Labels map[string]string `json:"labels,omitempty"`
+	Start  time.Time         `json:"start"`
+	End    time.Time         `json:"end"`
+	Reason string            `json:"reason,omitempty"`
+}
+
+// Matches returns true if check of canary is silenced at t
+func (s Silence) Matches(canary string, check pkg.GenericCheck, t time.Time) bool {
+	if t.Before(s.Start) || !t.Before(s.End) {
+		return false
+	}
+	if s.Canary != "" && s.Canary != canary {
+		return false
+	}
+	if s.Check != "" && s.Check != pkg.CheckKey("", check) {
+		return false
+	}
+	labels := check.GetLabels()
+	for k, v := range s.Labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+type silences struct {
+	mtx      sync.Mutex
+	silences map[string]Silence
+}
+
+var Silences = &silences{silences: make(map[string]Silence)}
+
+// Add validates and stores silence, returning it with its ID
+func (s *silences) Add(silence Silence) (Silence, error) {
+	if silence.Start.IsZero() {
+		silence.Start = time.Now()
+	}
+	if !silence.End.After(silence.Start) {
+		return silence, fmt.Errorf("end must be after start")
+	}
+	if silence.Canary == "" && silence.Check == "" && len(silence.Labels) == 0 {
+		return silence, fmt.Errorf("at least one of canary, check or labels is required")
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return silence, err
+	}
+	silence.ID = hex.EncodeToString(id)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.silences[silence.ID] = silence
+	return silence, nil
+}
+
+// Delete removes the silence with id and returns false if it does not exist
+func (s *silences) Delete(id string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, found := s.silences[id]
+	delete(s.silences, id)
+	return found
+}
+
+// List returns the silences that have not ended yet, ordered by start
+func (s *silences) List() []Silence {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.expire(time.Now())
+	list := []Silence{}
+	for _, silence := range s.silences {
+		list = append(list, silence)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Start.Equal(list[j].Start) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Start.Before(list[j].Start)
+	})
+	return list
+}
+
+// Match returns the silence covering check of canary at t, if any
+func (s *silences) Match(canary string, check pkg.GenericCheck, t time.Time) (Silence, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, silence := range s.silences {
+		if silence.Matches(canary, check, t) {
+			return silence, true
+		}
+	}
+	return Silence{}, false
+}
+
+func (s *silences) expire(now time.Time) {
+	for id, silence := range s.silences {
+		if !now.Before(silence.End) {
+			delete(s.silences, id)
+		}
+	}
+}
+
+// silenceRequest is a silence to create, which ends after Duration if End is not set
+type silenceRequest struct {
+	Silence
+	Duration string `json:"duration,omitempty"`
+}
+
+// Handler lists silences on GET /api/silences, creates one on POST /api/silences and deletes one on
+// DELETE /api/silences/<id>
+func Handler(w http.ResponseWriter, req *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/api/silences"), "/")
+	switch {
+	case req.Method == http.MethodGet && id == "":
+		writeJSON(w, http.StatusOK, Silences.List())
+	case req.Method == http.MethodPost && id == "":
+		request := silenceRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid silence: %v", err))
+			return
+		}
+		if request.End.IsZero() && request.Duration != "" {
+			duration, err := time.ParseDuration(request.Duration)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration: %v", err))
+				return
+			}
+			if request.Start.IsZero() {
+				request.Start = time.Now()
+			}
+			request.End = request.Start.Add(duration)
+		}
+		silence, err := Silences.Add(request.Silence)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Infof("Added silence %s until %s: %s", silence.ID, silence.End, silence.Reason)
+		writeJSON(w, http.StatusCreated, silence)
+	case req.Method == http.MethodDelete && id != "":
+		if !Silences.Delete(id) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("silence %s not found", id))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s %s is not supported", req.Method, req.URL.Path))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("Failed to marshal data: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -18,7 +18,7 @@ var (
 			Name: "canary_check_count",
 			Help: "The total number of checks",
 		},
-		[]string{"type", "endpoint", "name", "namespace"},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
 	)
 
 	OpsSuccessCount = prometheus.NewCounterVec(
@@ -26,7 +26,7 @@ var (
 			Name: "canary_check_success_count",
 			Help: "The total number of successful checks",
 		},
-		[]string{"type", "endpoint", "name", "namespace"},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
 	)
 
 	OpsFailedCount = prometheus.NewCounterVec(
@@ -34,7 +34,31 @@ var (
 			Name: "canary_check_failed_count",
 			Help: "The total number of failed checks",
 		},
-		[]string{"type", "endpoint", "name", "namespace"},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
+	)
+
+	OpsBlockedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_check_blocked_count",
+			Help: "The total number of checks that were not run as a check they depend on failed",
+		},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
+	)
+
+	OpsMaintenanceCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_check_maintenance_count",
+			Help: "The total number of checks that ran during a maintenance window or silence",
+		},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
+	)
+
+	OpsTimedOutCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_check_timed_out_count",
+			Help: "The total number of checks that timed out",
+		},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
 	)
 
 	RequestLatency = prometheus.NewHistogramVec(
@@ -43,7 +67,7 @@ var (
 			Help:    "A histogram of the response latency in milliseconds.",
 			Buckets: []float64{5, 10, 25, 50, 200, 500, 1000, 3000, 10000, 30000},
 		},
-		[]string{"type", "endpoint", "name", "namespace"},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
 	)
 
 	Guage = prometheus.NewGaugeVec(
@@ -51,7 +75,15 @@ var (
 			Name: "canary_check",
 			Help: "A gauge representing the canaries success (0) or failure (1)",
 		},
-		[]string{"type", "endpoint", "name", "namespace"},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
+	)
+
+	FlappingGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "canary_check_flapping",
+			Help: "A gauge representing whether the canary changes state too often (1) or not (0)",
+		},
+		[]string{"type", "endpoint", "name", "namespace", "check"},
 	)
 
 	GenericGauge = prometheus.NewGaugeVec(
@@ -81,7 +113,7 @@ var (
 )
 
 func init() {
-	prometheus.MustRegister(Guage, OpsCount, OpsSuccessCount, OpsFailedCount, RequestLatency, GenericGauge, GenericCounter, GenericHistogram)
+	prometheus.MustRegister(Guage, FlappingGauge, OpsCount, OpsSuccessCount, OpsFailedCount, OpsBlockedCount, OpsMaintenanceCount, OpsTimedOutCount, RequestLatency, GenericGauge, GenericCounter, GenericHistogram)
 }
 
 func Record(namespace, name string, result *pkg.CheckResult) {
@@ -94,15 +126,32 @@ func Record(namespace, name string, result *pkg.CheckResult) {
 	if endpoint == "" {
 		endpoint = result.Check.GetEndpoint()
 	}
+	// the check label identifies the check within the canary
+	check := pkg.CheckName(result.Check)
 	if logger.IsTraceEnabled() {
 		logger.Tracef(result.String())
 	}
-	OpsCount.WithLabelValues(checkType, endpoint, name, namespace).Inc()
+	OpsCount.WithLabelValues(checkType, endpoint, name, namespace, check).Inc()
+	if result.BlockedBy != "" {
+		OpsBlockedCount.WithLabelValues(checkType, endpoint, name, namespace, check).Inc()
+		return
+	}
+	if result.Maintenance {
+		// failures during maintenance are expected and are not counted, nor do they fail the gauge
+		Guage.WithLabelValues(checkType, endpoint, name, namespace, check).Set(0)
+		OpsMaintenanceCount.WithLabelValues(checkType, endpoint, name, namespace, check).Inc()
+		return
+	}
+	if result.Flapping {
+		FlappingGauge.WithLabelValues(checkType, endpoint, name, namespace, check).Set(1)
+	} else {
+		FlappingGauge.WithLabelValues(checkType, endpoint, name, namespace, check).Set(0)
+	}
 	if result.Pass {
-		Guage.WithLabelValues(checkType, endpoint, name, namespace).Set(0)
-		OpsSuccessCount.WithLabelValues(checkType, endpoint, name, namespace).Inc()
+		Guage.WithLabelValues(checkType, endpoint, name, namespace, check).Set(0)
+		OpsSuccessCount.WithLabelValues(checkType, endpoint, name, namespace, check).Inc()
 		if result.Duration > 0 {
-			RequestLatency.WithLabelValues(checkType, endpoint, name, namespace).Observe(float64(result.Duration))
+			RequestLatency.WithLabelValues(checkType, endpoint, name, namespace, check).Observe(float64(result.Duration))
 		}
 
 		for _, m := range result.Metrics {
@@ -116,7 +165,10 @@ func Record(namespace, name string, result *pkg.CheckResult) {
 			}
 		}
 	} else {
-		Guage.WithLabelValues(checkType, endpoint, name, namespace).Set(1)
-		OpsFailedCount.WithLabelValues(checkType, endpoint, name, namespace).Inc()
+		Guage.WithLabelValues(checkType, endpoint, name, namespace, check).Set(1)
+		OpsFailedCount.WithLabelValues(checkType, endpoint, name, namespace, check).Inc()
+		if result.TimedOut {
+			OpsTimedOutCount.WithLabelValues(checkType, endpoint, name, namespace, check).Inc()
+		}
 	}
 }
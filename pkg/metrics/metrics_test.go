@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"testing"
+
+	v1 "github.com/flanksource/canary-checker/api/v1"
+	"github.com/flanksource/canary-checker/pkg"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordGauge(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []pkg.CheckResult
+		want    float64
+	}{
+		{name: "passed", results: []pkg.CheckResult{{Pass: true}}, want: 0},
+		{name: "failed", results: []pkg.CheckResult{{Pass: false}}, want: 1},
+		{name: "recovered", results: []pkg.CheckResult{{Pass: false}, {Pass: true}}, want: 0},
+		{name: "failing when maintenance starts", results: []pkg.CheckResult{{Pass: false}, {Pass: false, Maintenance: true}}, want: 0},
+		{name: "failing after maintenance", results: []pkg.CheckResult{{Pass: false, Maintenance: true}, {Pass: false}}, want: 1},
+	}
+	for _, tc := range tests {
+		check := v1.HTTPCheck{Name: tc.name, Endpoint: "http://" + tc.name}
+		for _, result := range tc.results {
+			result := result
+			result.Check = check
+			Record("default", "http", &result)
+		}
+		gauge := Guage.WithLabelValues(check.GetType(), check.GetEndpoint(), "http", "default", pkg.CheckName(check))
+		if got := testutil.ToFloat64(gauge); got != tc.want {
+			t.Errorf("Test %s failed. Expected %v, but found %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -51,6 +51,27 @@ var (
 				Age:         7*24*time.Hour - 10*time.Minute, // 30 days
 				ContentType: "application/zip",
 			},
+			{
+				Bucket:      "tests-e2e-1",
+				Filename:    "/db/backups/orders/1.zip",
+				Size:        100,
+				Age:         2 * 24 * time.Hour, // 2 days
+				ContentType: "application/zip",
+			},
+			{
+				Bucket:      "tests-e2e-1",
+				Filename:    "/db/backups/orders/2.zip",
+				Size:        20,
+				Age:         24 * time.Hour, // 1 day
+				ContentType: "application/zip",
+			},
+			{
+				Bucket:      "tests-e2e-1",
+				Filename:    "/db/backups/users/1.zip",
+				Size:        100,
+				Age:         24 * time.Hour, // 1 day
+				ContentType: "application/zip",
+			},
 		},
 	}
 )
@@ -114,6 +135,34 @@ func TestE2E(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "s3_bucket_group_fail",
+			args: args{
+				pkg.ParseConfig("../fixtures/s3_bucket_group_fail.yaml"),
+			},
+			want: []pkg.CheckResult{
+				{
+					Pass:    false,
+					Invalid: false,
+					Message: "1 of 2 groups failed: orders dropped 80% from 100B to 20B",
+					Metrics: []pkg.Metric{},
+				},
+			},
+		},
+		{
+			name: "s3_bucket_size_drop_fail",
+			args: args{
+				pkg.ParseConfig("../fixtures/s3_bucket_size_drop_fail.yaml"),
+			},
+			want: []pkg.CheckResult{
+				{
+					Pass:    false,
+					Invalid: false,
+					Message: "Latest object dropped 80% from 100B to 20B",
+					Metrics: []pkg.Metric{},
+				},
+			},
+		},
 		{
 			name: "docker_push_pass",
 			args: args{
@@ -25,6 +25,10 @@ func TestRunChecks(t *testing.T) {
 	postgresFailConfig := pkg.ParseConfig("../fixtures/postgres_fail.yaml")
 	dnsFailConfig := pkg.ParseConfig("../fixtures/dns_fail.yaml")
 	dnsPassConfig := pkg.ParseConfig("../fixtures/dns_pass.yaml")
+	filesystemPassConfig := pkg.ParseConfig("../fixtures/filesystem_pass.yaml")
+	filesystemFailConfig := pkg.ParseConfig("../fixtures/filesystem_fail.yaml")
+	certificateFailConfig := pkg.ParseConfig("../fixtures/certificate_fail.yaml")
+	oidcFailConfig := pkg.ParseConfig("../fixtures/oidc_fail.yaml")
 
 	tests := []test{
 		{
@@ -166,6 +170,56 @@ func TestRunChecks(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "filesystem_pass",
+			args: args{filesystemPassConfig},
+			want: []pkg.CheckResult{
+				{
+					Check:   filesystemPassConfig.Filesystem[0],
+					Pass:    true,
+					Invalid: false,
+					Metrics: []pkg.Metric{},
+				},
+			},
+		},
+		{
+			name: "filesystem_fail",
+			args: args{filesystemFailConfig},
+			want: []pkg.CheckResult{
+				{
+					Check:   filesystemFailConfig.Filesystem[0],
+					Pass:    false,
+					Invalid: false,
+					Metrics: []pkg.Metric{},
+					Message: "Found 0 files required at least 1 files",
+				},
+			},
+		},
+		{
+			name: "certificate_fail",
+			args: args{certificateFailConfig},
+			want: []pkg.CheckResult{
+				{
+					Check:   certificateFailConfig.Certificate[0],
+					Pass:    false,
+					Invalid: false,
+					Metrics: []pkg.Metric{},
+					Message: "could not find any certificates",
+				},
+			},
+		},
+		{
+			name: "oidc_fail",
+			args: args{oidcFailConfig},
+			want: []pkg.CheckResult{
+				{
+					Check:   oidcFailConfig.OIDC[0],
+					Pass:    false,
+					Invalid: false,
+					Metrics: []pkg.Metric{},
+				},
+			},
+		},
 	}
 	runTests(t, tests)
 }